@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/nspcc-dev/neo-exporter/pkg/pool"
+	"github.com/spf13/viper"
+)
+
+const (
+	// list of monitored chains, every entry has the name, optional network
+	// name, type and the same rpc, contracts and nep17 sections as the single
+	// chain configuration.
+	cfgChains       = "chains"
+	cfgChainName    = "name"
+	cfgChainNetwork = "network"
+	cfgChainType    = "type"
+
+	// labels added to all metrics of chains from chains section: chain name,
+	// network magic and network name if it's set.
+	chainLabel   = "chain"
+	magicLabel   = "magic"
+	networkLabel = "network"
+)
+
+// Types of monitored chains.
+const (
+	// chainTypeMain is the Neo chain with NeoFS contract.
+	chainTypeMain = "main"
+	// chainTypeFS is the NeoFS chain.
+	chainTypeFS = "fs"
+	// chainTypeN3 is any Neo N3 chain, only its state and NEP-17 balances
+	// are collected.
+	chainTypeN3 = "n3"
+)
+
+// chainConfig is the configuration of a single monitored chain.
+type chainConfig struct {
+	// name is empty for the chain configured by chain section.
+	name    string
+	network string
+	typ     string
+	// cfg has the same layout as the single chain configuration: chain.rpc,
+	// contracts and nep17 sections.
+	cfg *viper.Viper
+}
+
+// chainSpecificKeys are root configuration keys not inherited by entries of
+// chains section.
+var chainSpecificKeys = []string{
+	cfgChains,
+	cfgChainFSChain,
+	cfgNeoFSContract,
+	"nep17",
+	prefix + delimiter + cfgNeoRPCEndpoint,
+	prefix + delimiter + cfgNeoRPCDiscoveryFile,
+}
+
+// parseChains returns chains configured by chains section or the single chain
+// configured by chain section if there are no chains. Settings of chain.rpc
+// section not specific to the chain (timeouts, retries, etc.) are defaults for
+// chains section entries.
+func parseChains(cfg *viper.Viper) ([]chainConfig, error) {
+	var entries []map[string]any
+
+	if err := cfg.UnmarshalKey(cfgChains, &entries); err != nil {
+		return nil, fmt.Errorf("cfg %s parse: %w", cfgChains, err)
+	}
+
+	if len(entries) == 0 {
+		typ := chainTypeMain
+		if cfg.GetBool(cfgChainFSChain) {
+			typ = chainTypeFS
+		}
+
+		return []chainConfig{{typ: typ, cfg: cfg}}, nil
+	}
+
+	res := make([]chainConfig, 0, len(entries))
+
+	for i, e := range entries {
+		c, err := parseChain(cfg, e)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: %w", cfgChains, i, err)
+		}
+
+		if slices.ContainsFunc(res, func(other chainConfig) bool { return other.id() == c.id() }) {
+			return nil, fmt.Errorf("%s[%d]: duplicate chain %q", cfgChains, i, c.id())
+		}
+
+		res = append(res, c)
+	}
+
+	return res, nil
+}
+
+func parseChain(cfg *viper.Viper, entry map[string]any) (chainConfig, error) {
+	var (
+		v   = viper.New()
+		res = chainConfig{cfg: v}
+	)
+
+	for _, key := range cfg.AllKeys() {
+		if !slices.ContainsFunc(chainSpecificKeys, func(k string) bool {
+			return key == k || strings.HasPrefix(key, k+delimiter)
+		}) {
+			v.SetDefault(key, cfg.Get(key))
+		}
+	}
+
+	section := make(map[string]any)
+
+	for key, value := range entry {
+		switch key = strings.ToLower(key); key {
+		case cfgChainName:
+			res.name, _ = value.(string)
+		case cfgChainNetwork:
+			res.network, _ = value.(string)
+		case cfgChainType:
+			res.typ, _ = value.(string)
+		case "rpc":
+			section[prefix] = map[string]any{key: value}
+		case "contracts", "nep17":
+			section[key] = value
+		default:
+			return res, fmt.Errorf("unknown chain setting %q", key)
+		}
+	}
+
+	if res.name == "" {
+		return res, errors.New("empty chain name")
+	}
+
+	switch res.typ {
+	case chainTypeMain, chainTypeFS, chainTypeN3:
+	default:
+		return res, fmt.Errorf("chain %s: invalid type %q, must be %q, %q or %q", res.name, res.typ,
+			chainTypeMain, chainTypeFS, chainTypeN3)
+	}
+
+	if err := v.MergeConfigMap(section); err != nil {
+		return res, fmt.Errorf("chain %s: %w", res.name, err)
+	}
+
+	return res, nil
+}
+
+// id returns the chain name qualified by the network name if it's set.
+func (c chainConfig) id() string {
+	if c.network == "" {
+		return c.name
+	}
+
+	return c.network + "/" + c.name
+}
+
+// chainHealth reports the chain unavailable until its RPC pool is created.
+type chainHealth struct {
+	pool atomic.Pointer[pool.Pool]
+}
+
+// Healthy implements [monitor.HealthChecker].
+func (h *chainHealth) Healthy() bool {
+	p := h.pool.Load()
+	return p != nil && p.Healthy()
+}
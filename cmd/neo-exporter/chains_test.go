@@ -0,0 +1,149 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChains(t *testing.T) {
+	newConfig := func(t *testing.T, config string) *viper.Viper {
+		cfg := viper.New()
+		DefaultConfiguration(cfg)
+		cfg.SetConfigType("yaml")
+		require.NoError(t, cfg.ReadConfig(strings.NewReader(config)))
+
+		return cfg
+	}
+
+	t.Run("single", func(t *testing.T) {
+		chains, err := parseChains(newConfig(t, `
+chain:
+  fschain: true
+  rpc:
+    endpoint: http://localhost:30333
+`))
+		require.NoError(t, err)
+		require.Len(t, chains, 1)
+		require.Empty(t, chains[0].name)
+		require.Equal(t, chainTypeFS, chains[0].typ)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		chains, err := parseChains(newConfig(t, `
+chain:
+  rpc:
+    endpoint: http://localhost:30333
+    dial_timeout: 5s
+metrics:
+  concurrency: 4
+nep17:
+  - contract: gas
+    balanceOf:
+      - NgzKezqwkHGjSPrVsf75YFNjQD5tm6CtkB
+chains:
+  - name: main
+    type: main
+    rpc:
+      endpoint: http://localhost:20332
+    contracts:
+      neofs: 902e0d38da5e513b6d07c1c55b85e77d3dce8063
+  - name: fs
+    type: fs
+    rpc:
+      endpoint: http://localhost:30333
+      dial_timeout: 10s
+    nep17:
+      - contract: gas
+        balanceOf:
+          - NgzKezqwkHGjSPrVsf75YFNjQD5tm6CtkB
+`))
+		require.NoError(t, err)
+		require.Len(t, chains, 2)
+
+		main, fs := chains[0], chains[1]
+
+		require.Equal(t, "main", main.name)
+		require.Equal(t, chainTypeMain, main.typ)
+		require.Equal(t, "902e0d38da5e513b6d07c1c55b85e77d3dce8063", main.cfg.GetString(cfgNeoFSContract))
+		require.Equal(t, "http://localhost:20332", main.cfg.GetString(prefix+delimiter+cfgNeoRPCEndpoint))
+		// Common settings are inherited, chain specific ones are not.
+		require.Equal(t, 5*time.Second, main.cfg.GetDuration(prefix+delimiter+cfgNeoRPCDialTimeout))
+		require.Equal(t, 3, main.cfg.GetInt(prefix+delimiter+cfgNeoRPCRetryAttempts))
+		require.Equal(t, 4, main.cfg.GetInt(cfgMetricsConcurrency))
+		require.Nil(t, main.cfg.Get("nep17"))
+
+		require.Equal(t, "fs", fs.name)
+		require.Equal(t, chainTypeFS, fs.typ)
+		require.Equal(t, 10*time.Second, fs.cfg.GetDuration(prefix+delimiter+cfgNeoRPCDialTimeout))
+		require.Empty(t, fs.cfg.GetString(cfgNeoFSContract))
+		require.Len(t, fs.cfg.Get("nep17"), 1)
+	})
+
+	t.Run("networks", func(t *testing.T) {
+		chains, err := parseChains(newConfig(t, `
+chains:
+  - name: main
+    network: mainnet
+    type: main
+  - name: main
+    network: testnet
+    type: main
+  - name: main
+    type: main
+`))
+		require.NoError(t, err)
+		require.Len(t, chains, 3)
+
+		require.Equal(t, "mainnet", chains[0].network)
+		require.Equal(t, "mainnet/main", chains[0].id())
+		require.Equal(t, "testnet/main", chains[1].id())
+		require.Equal(t, "main", chains[2].id())
+	})
+
+	for _, tc := range []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{name: "no name", config: `
+chains:
+  - type: main
+`, err: "empty chain name"},
+		{name: "invalid type", config: `
+chains:
+  - name: a
+    type: side
+`, err: "invalid type"},
+		{name: "duplicate", config: `
+chains:
+  - name: a
+    type: main
+  - name: a
+    type: fs
+`, err: "duplicate chain"},
+		{name: "duplicate in network", config: `
+chains:
+  - name: a
+    network: testnet
+    type: main
+  - name: a
+    network: testnet
+    type: fs
+`, err: "duplicate chain"},
+		{name: "unknown setting", config: `
+chains:
+  - name: a
+    type: main
+    fschain: true
+`, err: "unknown chain setting"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseChains(newConfig(t, tc.config))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
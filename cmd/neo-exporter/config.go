@@ -1,9 +1,26 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/nspcc-dev/neo-exporter/pkg/model"
+	"github.com/nspcc-dev/neo-exporter/pkg/monitor"
+	"github.com/nspcc-dev/neo-exporter/pkg/pool"
+	"github.com/prometheus/client_golang/prometheus"
+	prommodel "github.com/prometheus/common/model"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
@@ -23,26 +40,362 @@ const (
 	cfgNeoRPCDialTimeout                = "rpc.dial_timeout"
 	cfgNeoRPCRecheckInterval            = "rpc.health_recheck_interval"
 	cfgNeoRPCPoolConnectionSleepTimeout = "rpc.startup_pool_connection_sleep_timeout"
+	cfgNeoRPCMaxHeightLag               = "rpc.max_height_lag"
+	cfgNeoRPCStrategy                   = "rpc.strategy"
+	cfgNeoRPCCallTimeout                = "rpc.call_timeout"
+	cfgNeoRPCRetryAttempts              = "rpc.retry.attempts"
+	cfgNeoRPCRetryMinBackoff            = "rpc.retry.min_backoff"
+	cfgNeoRPCRetryMaxBackoff            = "rpc.retry.max_backoff"
+	cfgNeoRPCBreakerThreshold           = "rpc.circuit_breaker.threshold"
+	cfgNeoRPCBreakerCooldown            = "rpc.circuit_breaker.cooldown"
+	cfgNeoRPCCache                      = "rpc.cache"
+	cfgNeoRPCDiscoveryFile              = "rpc.discovery.file"
+	cfgNeoRPCDiscoveryFileInterval      = "rpc.discovery.file_check_interval"
+	cfgNeoRPCDiscoveryPeersTemplate     = "rpc.discovery.peers_template"
+	cfgNeoRPCDiscoveryPeersPriority     = "rpc.discovery.peers_priority"
+	cfgNeoRPCDiscoveryPeersInterval     = "rpc.discovery.peers_interval"
 
 	// monitor prometheus expose config values.
-	cfgMetricsEndpoint = "metrics.endpoint"
-	cfgMetricsInterval = "metrics.interval"
+	cfgMetricsEndpoint        = "metrics.endpoint"
+	cfgMetricsInterval        = "metrics.interval"
+	cfgMetricsBlocks          = "metrics.blocks"
+	cfgMetricsConcurrency     = "metrics.concurrency"
+	cfgMetricsMode            = "metrics.mode"
+	cfgMetricsMaxAge          = "metrics.max_age"
+	cfgMetricsHealthIntervals = "metrics.health_intervals"
+	cfgMetricsStale           = "metrics.stale"
+	cfgMetricsStaleCycles     = "metrics.stale_cycles"
+	cfgMetricsStaleAge        = "metrics.stale_age"
+	cfgMetricsLabels          = "metrics.labels"
+
+	// collector switches and schedules, prefixed with the collector name.
+	cfgCollectors        = "collectors"
+	cfgCollectorEnabled  = "enabled"
+	cfgCollectorInterval = "interval"
+	cfgCollectorBlocks   = "blocks"
+	cfgCollectorEpoch    = "epoch"
+	cfgCollectorStale    = "stale"
+	cfgCollectorCycles   = "stale_cycles"
+	cfgCollectorAge      = "stale_age"
 
 	// level of logging.
 	cfgLoggerLevel = "logger.level"
+
+	// time to wait for the job and metric requests in progress on shutdown.
+	cfgShutdownTimeout = "shutdown_timeout"
+)
+
+// Metric collection modes.
+const (
+	// metricsModeBackground updates metrics by collectors running on their
+	// schedules.
+	metricsModeBackground = "background"
+	// metricsModeScrape runs collectors on scrape.
+	metricsModeScrape = "scrape"
 )
 
 func DefaultConfiguration(cfg *viper.Viper) {
 	cfg.SetDefault(prefix+delimiter+cfgNeoRPCEndpoint, "")
 	cfg.SetDefault(prefix+delimiter+cfgNeoRPCDialTimeout, time.Minute)
+	cfg.SetDefault(prefix+delimiter+cfgNeoRPCMaxHeightLag, 10)
+	cfg.SetDefault(prefix+delimiter+cfgNeoRPCCallTimeout, 30*time.Second)
+	cfg.SetDefault(prefix+delimiter+cfgNeoRPCRetryAttempts, 3)
+	cfg.SetDefault(prefix+delimiter+cfgNeoRPCRetryMinBackoff, 100*time.Millisecond)
+	cfg.SetDefault(prefix+delimiter+cfgNeoRPCRetryMaxBackoff, 2*time.Second)
+	cfg.SetDefault(prefix+delimiter+cfgNeoRPCBreakerThreshold, 5)
+	cfg.SetDefault(prefix+delimiter+cfgNeoRPCBreakerCooldown, 30*time.Second)
+	cfg.SetDefault(prefix+delimiter+cfgNeoRPCDiscoveryFileInterval, 10*time.Second)
+	cfg.SetDefault(prefix+delimiter+cfgNeoRPCDiscoveryPeersInterval, time.Minute)
 
 	cfg.SetDefault(cfgMetricsEndpoint, ":16512")
 	cfg.SetDefault(cfgMetricsInterval, 15*time.Second)
+	cfg.SetDefault(cfgMetricsConcurrency, monitor.DefaultConcurrency)
+	cfg.SetDefault(cfgMetricsMode, metricsModeBackground)
+	cfg.SetDefault(cfgMetricsMaxAge, 15*time.Second)
+	cfg.SetDefault(cfgMetricsHealthIntervals, 3)
+	cfg.SetDefault(cfgMetricsStale, string(monitor.StaleKeep))
+	cfg.SetDefault(cfgMetricsStaleCycles, 3)
 
 	cfg.SetDefault(cfgLoggerLevel, "info")
+	cfg.SetDefault(cfgShutdownTimeout, 10*time.Second)
 	cfg.SetDefault(prefix+delimiter+cfgNeoRPCPoolConnectionSleepTimeout, 3*time.Second)
 }
 
+// parseCollectorSwitches reads enabled flags of collectors from the
+// configuration. Collectors listed in the configuration section are included
+// even if unknown, so that misspelled names are reported.
+func parseCollectorSwitches(cfg *viper.Viper, names []string) map[string]bool {
+	res := make(map[string]bool)
+
+	for _, name := range slices.Concat(names, slices.Collect(maps.Keys(cfg.GetStringMap(cfgCollectors)))) {
+		key := cfgCollectors + delimiter + name + delimiter + cfgCollectorEnabled
+
+		if cfg.IsSet(key) {
+			res[name] = cfg.GetBool(key)
+		} else if !slices.Contains(names, name) {
+			res[name] = true
+		}
+	}
+
+	return res
+}
+
+// parseLabels reads constant labels added to all metrics. Labels added by the
+// exporter itself can't be set.
+func parseLabels(cfg *viper.Viper) (prometheus.Labels, error) {
+	res := make(prometheus.Labels)
+
+	for name, value := range cfg.GetStringMapString(cfgMetricsLabels) {
+		if !prommodel.LabelName(name).IsValid() {
+			return nil, fmt.Errorf("invalid %q configuration: invalid label name %q", cfgMetricsLabels, name)
+		}
+
+		if slices.Contains([]string{chainLabel, magicLabel, networkLabel}, name) {
+			return nil, fmt.Errorf("invalid %q configuration: label %q is set by exporter", cfgMetricsLabels, name)
+		}
+
+		res[name] = value
+	}
+
+	return res, nil
+}
+
+// parseSchedules reads schedules of the given collectors. Collectors without
+// own interval and block settings use metrics.interval and metrics.blocks.
+// Only collectors driven by blocks or epoch are allowed to have no interval.
+func parseSchedules(cfg *viper.Viper, collectors []monitor.Collector) (map[string]monitor.Schedule, error) {
+	res := make(map[string]monitor.Schedule, len(collectors))
+
+	for _, c := range collectors {
+		var (
+			key = cfgCollectors + delimiter + c.Name() + delimiter
+			s   = monitor.Schedule{
+				Interval: cfg.GetDuration(cfgMetricsInterval),
+				Blocks:   cfg.GetUint32(cfgMetricsBlocks),
+				Epoch:    cfg.GetBool(key + cfgCollectorEpoch),
+			}
+		)
+
+		if cfg.IsSet(key + cfgCollectorInterval) {
+			s.Interval = cfg.GetDuration(key + cfgCollectorInterval)
+		}
+
+		if cfg.IsSet(key + cfgCollectorBlocks) {
+			s.Blocks = cfg.GetUint32(key + cfgCollectorBlocks)
+		}
+
+		if s.Interval < 0 || s.Interval == 0 && s.Blocks == 0 && !s.Epoch {
+			return nil, fmt.Errorf("invalid %s interval: %s, must be positive unless %s or %s is set",
+				c.Name(), s.Interval, cfgCollectorBlocks, cfgCollectorEpoch)
+		}
+
+		res[c.Name()] = s
+	}
+
+	return res, nil
+}
+
+// parseStalePolicies reads stale series policies of the given collectors.
+// Collectors without own settings use metrics.stale, metrics.stale_cycles and
+// metrics.stale_age, "keep" for 3 runs by default.
+func parseStalePolicies(cfg *viper.Viper, collectors []monitor.Collector) (map[string]monitor.StalePolicy, error) {
+	res := make(map[string]monitor.StalePolicy, len(collectors))
+
+	for _, c := range collectors {
+		var (
+			key  = cfgCollectors + delimiter + c.Name() + delimiter
+			mode = cfgMetricsStale
+			p    = monitor.StalePolicy{
+				Cycles: cfg.GetInt(cfgMetricsStaleCycles),
+				Age:    cfg.GetBool(cfgMetricsStaleAge),
+			}
+		)
+
+		if cfg.IsSet(key + cfgCollectorStale) {
+			mode = key + cfgCollectorStale
+		}
+
+		if cfg.IsSet(key + cfgCollectorCycles) {
+			p.Cycles = cfg.GetInt(key + cfgCollectorCycles)
+		}
+
+		if cfg.IsSet(key + cfgCollectorAge) {
+			p.Age = cfg.GetBool(key + cfgCollectorAge)
+		}
+
+		switch p.Mode = monitor.StaleMode(cfg.GetString(mode)); p.Mode {
+		case monitor.StaleDrop, monitor.StaleKeep, monitor.StaleMark:
+		default:
+			return nil, fmt.Errorf("invalid %q: %q, must be %q, %q or %q", mode, p.Mode,
+				monitor.StaleDrop, monitor.StaleKeep, monitor.StaleMark)
+		}
+
+		res[c.Name()] = p
+	}
+
+	return res, nil
+}
+
+// parseEndpoints reads RPC endpoints from the given key. Endpoints can be set
+// as plain address strings (also space-separated in env variables) or as
+// structures with additional options.
+func parseEndpoints(cfg *viper.Viper, key string) ([]pool.Endpoint, error) {
+	var items []model.RPCEndpoint
+
+	if s, ok := cfg.Get(key).(string); ok {
+		for _, addr := range strings.Fields(s) {
+			items = append(items, model.RPCEndpoint{Address: addr})
+		}
+	} else {
+		err := cfg.UnmarshalKey(key, &items, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			endpointFromStringHook,
+		)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	endpoints := make([]pool.Endpoint, 0, len(items))
+	for _, it := range items {
+		if it.Address == "" {
+			return nil, fmt.Errorf("empty endpoint address in %s", key)
+		}
+
+		ep, err := newEndpoint(it)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", it.Address, err)
+		}
+
+		endpoints = append(endpoints, ep)
+	}
+
+	return endpoints, nil
+}
+
+func newEndpoint(it model.RPCEndpoint) (pool.Endpoint, error) {
+	var (
+		ep = pool.Endpoint{
+			Address:  it.Address,
+			Priority: it.Priority,
+		}
+		err error
+	)
+
+	ep.TLS, err = tlsConfig(it.TLS)
+	if err != nil {
+		return ep, err
+	}
+
+	if it.Proxy != "" {
+		ep.Proxy, err = url.Parse(it.Proxy)
+		if err != nil {
+			return ep, fmt.Errorf("parse proxy: %w", err)
+		}
+	}
+
+	var header = make(http.Header)
+
+	for _, h := range it.Headers {
+		v, err := readSecret(h.Secret)
+		if err != nil {
+			return ep, fmt.Errorf("header %s: %w", h.Name, err)
+		}
+
+		header.Add(h.Name, v)
+	}
+
+	if it.BearerToken != nil {
+		token, err := readSecret(*it.BearerToken)
+		if err != nil {
+			return ep, fmt.Errorf("bearer token: %w", err)
+		}
+
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	if it.BasicAuth != nil {
+		password, err := readSecret(it.BasicAuth.Password)
+		if err != nil {
+			return ep, fmt.Errorf("basic auth password: %w", err)
+		}
+
+		creds := base64.StdEncoding.EncodeToString([]byte(it.BasicAuth.Username + ":" + password))
+		header.Set("Authorization", "Basic "+creds)
+	}
+
+	if len(header) != 0 {
+		ep.Header = header
+	}
+
+	return ep, nil
+}
+
+// tlsConfig returns TLS configuration with custom CA and client certificate,
+// nil if neither is set.
+func tlsConfig(c model.RPCTLS) (*tls.Config, error) {
+	if c.CA == "" && c.Cert == "" && c.Key == "" {
+		return nil, nil
+	}
+
+	var conf = new(tls.Config)
+
+	if c.CA != "" {
+		data, err := os.ReadFile(c.CA)
+		if err != nil {
+			return nil, fmt.Errorf("read CA: %w", err)
+		}
+
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CA)
+		}
+	}
+
+	if c.Cert != "" || c.Key != "" {
+		cert, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return conf, nil
+}
+
+// readSecret returns the secret value from the configured source.
+func readSecret(s model.Secret) (string, error) {
+	switch {
+	case s.Value != "" && s.File == "" && s.Env == "":
+		return s.Value, nil
+	case s.File != "" && s.Value == "" && s.Env == "":
+		data, err := os.ReadFile(s.File)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	case s.Env != "" && s.Value == "" && s.File == "":
+		v, ok := os.LookupEnv(s.Env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", s.Env)
+		}
+
+		return v, nil
+	default:
+		return "", errors.New("exactly one of value, file and env must be set")
+	}
+}
+
+func endpointFromStringHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeFor[model.RPCEndpoint]() {
+		return data, nil
+	}
+
+	return model.RPCEndpoint{Address: data.(string)}, nil
+}
+
 func WithLevel(level string) zap.AtomicLevel {
 	return safeLevel(level)
 }
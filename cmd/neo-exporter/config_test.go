@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/pkg/monitor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+type namedCollector string
+
+func (c namedCollector) Name() string                         { return string(c) }
+func (c namedCollector) Process(context.Context) (int, error) { return 0, nil }
+func (c namedCollector) Metrics() []prometheus.Collector      { return nil }
+
+func TestParseSchedules(t *testing.T) {
+	var collectors = []monitor.Collector{namedCollector("netmap"), namedCollector("proxy")}
+
+	parse := func(t *testing.T, config string) (map[string]monitor.Schedule, error) {
+		cfg := viper.New()
+		DefaultConfiguration(cfg)
+		cfg.SetConfigType("yaml")
+		require.NoError(t, cfg.ReadConfig(strings.NewReader(config)))
+
+		return parseSchedules(cfg, collectors)
+	}
+
+	t.Run("default", func(t *testing.T) {
+		schedules, err := parse(t, ``)
+		require.NoError(t, err)
+		require.Equal(t, map[string]monitor.Schedule{
+			"netmap": {Interval: 15 * time.Second},
+			"proxy":  {Interval: 15 * time.Second},
+		}, schedules)
+	})
+
+	t.Run("block and epoch driven", func(t *testing.T) {
+		schedules, err := parse(t, `
+metrics:
+  interval: 0s
+  blocks: 5
+collectors:
+  netmap:
+    epoch: true
+    blocks: 0
+`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]monitor.Schedule{
+			"netmap": {Epoch: true},
+			"proxy":  {Blocks: 5},
+		}, schedules)
+	})
+
+	for name, config := range map[string]string{
+		"zero interval": `
+metrics:
+  interval: 0s
+`,
+		"negative interval": `
+collectors:
+  proxy:
+    interval: -1s
+    blocks: 1
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parse(t, config)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseStalePolicies(t *testing.T) {
+	var collectors = []monitor.Collector{namedCollector("netmap"), namedCollector("proxy")}
+
+	parse := func(t *testing.T, config string) (map[string]monitor.StalePolicy, error) {
+		cfg := viper.New()
+		DefaultConfiguration(cfg)
+		cfg.SetConfigType("yaml")
+		require.NoError(t, cfg.ReadConfig(strings.NewReader(config)))
+
+		return parseStalePolicies(cfg, collectors)
+	}
+
+	t.Run("default", func(t *testing.T) {
+		policies, err := parse(t, ``)
+		require.NoError(t, err)
+		require.Equal(t, map[string]monitor.StalePolicy{
+			"netmap": {Mode: monitor.StaleKeep, Cycles: 3},
+			"proxy":  {Mode: monitor.StaleKeep, Cycles: 3},
+		}, policies)
+	})
+
+	t.Run("global", func(t *testing.T) {
+		policies, err := parse(t, `
+metrics:
+  stale: keep
+collectors:
+  proxy:
+    stale: mark
+    stale_age: true
+`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]monitor.StalePolicy{
+			"netmap": {Mode: monitor.StaleKeep, Cycles: 3},
+			"proxy":  {Mode: monitor.StaleMark, Cycles: 3, Age: true},
+		}, policies)
+	})
+
+	t.Run("collector", func(t *testing.T) {
+		policies, err := parse(t, `
+metrics:
+  stale_age: true
+collectors:
+  netmap:
+    stale: keep
+    stale_cycles: 10
+`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]monitor.StalePolicy{
+			"netmap": {Mode: monitor.StaleKeep, Cycles: 10, Age: true},
+			"proxy":  {Mode: monitor.StaleKeep, Cycles: 3, Age: true},
+		}, policies)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parse(t, `
+metrics:
+  stale: forever
+`)
+		require.Error(t, err)
+
+		_, err = parse(t, `
+collectors:
+  proxy:
+    stale: ""
+`)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/internal/chaintest"
+	"github.com/nspcc-dev/neo-exporter/pkg/monitor"
+	"github.com/nspcc-dev/neo-exporter/pkg/pool"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestFSChainJobE2E runs FS chain job against the chain with NeoFS contracts
+// deployed.
+func TestFSChainJobE2E(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = t.Context()
+		chain = chaintest.NewFS(t)
+		nodes = make([]*keys.PrivateKey, 3)
+	)
+
+	for i := range nodes {
+		var err error
+		nodes[i], err = keys.NewPrivateKey()
+		require.NoError(t, err)
+	}
+
+	chain.AddNode(t, chaintest.StorageNode{
+		Key:        nodes[0],
+		Addresses:  []string{"/ip4/10.0.0.1/tcp/8080"},
+		Attributes: map[string]string{"Capacity": "100", "UN-LOCODE": "RU MOW"},
+	})
+	chain.AddNode(t, chaintest.StorageNode{
+		Key:        nodes[1],
+		Addresses:  []string{"/ip4/10.0.0.2/tcp/8080"},
+		Attributes: map[string]string{"Capacity": "200", "UN-LOCODE": "RU LED"},
+	})
+	chain.NewEpoch(t)
+	// The last node is the candidate only.
+	chain.AddNode(t, chaintest.StorageNode{
+		Key:        nodes[2],
+		Addresses:  []string{"/ip4/10.0.0.3/tcp/8080"},
+		Attributes: map[string]string{"Capacity": "300", "UN-LOCODE": "RU MOW"},
+	})
+
+	chain.TransferGAS(t, nodes[0].GetScriptHash(), 5_0000_0000)
+	chain.DepositNotary(t, nodes[0].GetScriptHash(), 2_0000_0000)
+	chain.DepositNotary(t, chain.CommitteeKey(t).GetScriptHash(), 3_0000_0000)
+	chain.TransferGAS(t, chain.Proxy, 10_0000_0000)
+	chain.Mint(t, nodes[0].GetScriptHash(), 7_0000_0000_0000)
+
+	cnr := chain.CreateContainer(t, nodes[0].GetScriptHash(), nodes[0], nodes[1])
+	chain.PutReport(t, cnr, nodes[0], 1024, 3)
+	chain.PutReport(t, cnr, nodes[1], 2048, 5)
+
+	p := newE2EPool(t, chain.Chain)
+
+	job, err := fsChainJob(ctx, viper.New(), p, zap.NewNop())
+	require.NoError(t, err)
+
+	reg := monitor.NewRegistry(nil)
+	monitor.RegisterMetrics(reg, job.Collectors())
+
+	job.Process(ctx)
+
+	height := chain.Chain.Chain.GetStateModule().CurrentLocalHeight()
+	root, err := chain.Chain.Chain.GetStateModule().GetStateRoot(height)
+	require.NoError(t, err)
+
+	const expected = `
+# HELP neo_exporter_alphabet_balance_notary Side chain notary balance of alphabet nodes
+# TYPE neo_exporter_alphabet_balance_notary gauge
+neo_exporter_alphabet_balance_notary{key="{committee}"} 3
+# HELP neo_exporter_alphabet_public_key Alphabet public keys in chain
+# TYPE neo_exporter_alphabet_public_key gauge
+neo_exporter_alphabet_public_key{key="{committee}"} 1
+# HELP neo_exporter_candidate_info Candidate node info
+# TYPE neo_exporter_candidate_info gauge
+neo_exporter_candidate_info{host="10.0.0.1",last_active_epoch="0"} 1
+neo_exporter_candidate_info{host="10.0.0.2",last_active_epoch="0"} 1
+neo_exporter_candidate_info{host="10.0.0.3",last_active_epoch="1"} 1
+# HELP neo_exporter_chain_height Chain height in blocks
+# TYPE neo_exporter_chain_height gauge
+neo_exporter_chain_height{host="{host}"} {height}
+# HELP neo_exporter_chain_state Chain state hash in specific height
+# TYPE neo_exporter_chain_state gauge
+neo_exporter_chain_state{hash="{state}",host="{host}"} {height}
+# HELP neo_exporter_container_objects Number of objects in the container
+# TYPE neo_exporter_container_objects gauge
+neo_exporter_container_objects{container="{container}"} 8
+# HELP neo_exporter_container_size Size of container
+# TYPE neo_exporter_container_size gauge
+neo_exporter_container_size{container="{container}"} 3072
+# HELP neo_exporter_containers_number Number of available containers
+# TYPE neo_exporter_containers_number gauge
+neo_exporter_containers_number 1
+# HELP neo_exporter_containers_objects Total number of objects in available containers
+# TYPE neo_exporter_containers_objects gauge
+neo_exporter_containers_objects 8
+# HELP neo_exporter_containers_size Total size of available containers
+# TYPE neo_exporter_containers_size gauge
+neo_exporter_containers_size 3072
+# HELP neo_exporter_epoch Epoch number of NeoFS network
+# TYPE neo_exporter_epoch gauge
+neo_exporter_epoch 1
+# HELP neo_exporter_fs_chain_supply FS chain total supply of balance contract
+# TYPE neo_exporter_fs_chain_supply gauge
+neo_exporter_fs_chain_supply 7
+# HELP neo_exporter_ir_balance Side chain GAS amount of inner ring nodes
+# TYPE neo_exporter_ir_balance gauge
+neo_exporter_ir_balance{key="{committee}"} {committee_balance}
+# HELP neo_exporter_netmap Locations where NeoFS storage nodes are located
+# TYPE neo_exporter_netmap gauge
+neo_exporter_netmap{latitude="55.7500",location="Moskva",longitude="37.6000"} 1
+neo_exporter_netmap{latitude="59.8833",location="Saint Petersburg (ex Leningrad)",longitude="30.2500"} 1
+# HELP neo_exporter_netmap_dropped Amount of nodes that will be dropped from network in the next epoch
+# TYPE neo_exporter_netmap_dropped gauge
+neo_exporter_netmap_dropped 0
+# HELP neo_exporter_netmap_new Amount of nodes that will be added to network in the next epoch
+# TYPE neo_exporter_netmap_new gauge
+neo_exporter_netmap_new 1
+# HELP neo_exporter_proxy_balance Side chain GAS amount of proxy contract
+# TYPE neo_exporter_proxy_balance gauge
+neo_exporter_proxy_balance 10
+# HELP neo_exporter_sn_balance Side chain GAS amount of storage nodes
+# TYPE neo_exporter_sn_balance gauge
+neo_exporter_sn_balance{key="{node0}"} 5
+neo_exporter_sn_balance{key="{node1}"} 0
+# HELP neo_exporter_sn_balance_notary Side chain notary balance of storage nodes
+# TYPE neo_exporter_sn_balance_notary gauge
+neo_exporter_sn_balance_notary{key="{node0}"} 2
+neo_exporter_sn_balance_notary{key="{node1}"} 0
+# HELP neo_exporter_sn_capacity Storage node capacity (GB)
+# TYPE neo_exporter_sn_capacity gauge
+neo_exporter_sn_capacity{host="10.0.0.1",key="{node0}"} 100
+neo_exporter_sn_capacity{host="10.0.0.2",key="{node1}"} 200
+# HELP neo_exporter_sn_capacity_total Storage nodes total capacity (GB)
+# TYPE neo_exporter_sn_capacity_total gauge
+neo_exporter_sn_capacity_total 300
+`
+
+	committee := chain.CommitteeKey(t)
+
+	requireExposition(t, reg, expected, map[string]string{
+		"committee":         committee.StringCompressed(),
+		"committee_balance": gasBalance(chain.Chain, committee.GetScriptHash()),
+		"node0":             nodes[0].PublicKey().StringCompressed(),
+		"node1":             nodes[1].PublicKey().StringCompressed(),
+		"container":         cnr.String(),
+		"host":              chain.Address,
+		"height":            strconv.FormatUint(uint64(height), 10),
+		"state":             root.Hash().String(),
+	})
+}
+
+// TestMainChainJobE2E runs main chain job against the chain with NeoFS
+// contract deployed.
+func TestMainChainJobE2E(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = t.Context()
+		chain = chaintest.NewMain(t)
+		cfg   = viper.New()
+	)
+
+	chain.Deposit(t, 100_0000_0000)
+
+	cfg.Set(cfgNeoFSContract, chain.NeoFS.StringLE())
+
+	p := newE2EPool(t, chain.Chain)
+
+	job, err := mainChainJob(ctx, cfg, p, zap.NewNop())
+	require.NoError(t, err)
+
+	reg := monitor.NewRegistry(nil)
+	monitor.RegisterMetrics(reg, job.Collectors())
+
+	job.Process(ctx)
+
+	const expected = `
+# HELP neo_exporter_alphabet_balance Main chain GAS amount of alphabet nodes
+# TYPE neo_exporter_alphabet_balance gauge
+neo_exporter_alphabet_balance{key="{committee}"} {committee_balance}
+# HELP neo_exporter_alphabet_public_key Alphabet public keys in chain
+# TYPE neo_exporter_alphabet_public_key gauge
+neo_exporter_alphabet_public_key{key="{committee}"} 1
+# HELP neo_exporter_main_chain_supply Main chain GAS amount of neofs contract
+# TYPE neo_exporter_main_chain_supply gauge
+neo_exporter_main_chain_supply 100
+`
+
+	committee := chain.CommitteeKey(t)
+
+	requireExposition(t, reg, expected, map[string]string{
+		"committee":         committee.StringCompressed(),
+		"committee_balance": gasBalance(chain.Chain, committee.GetScriptHash()),
+	})
+}
+
+// TestChainsE2E runs jobs of different chain types in one process, their
+// metrics are distinguished by the chain label.
+func TestChainsE2E(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = t.Context()
+		chain = chaintest.NewMain(t)
+		cfg   = viper.New()
+	)
+
+	cfg.Set(cfgNeoFSContract, chain.NeoFS.StringLE())
+
+	p := newE2EPool(t, chain.Chain)
+
+	mainJob, err := mainChainJob(ctx, cfg, p, zap.NewNop())
+	require.NoError(t, err)
+
+	n3Job, err := n3ChainJob(ctx, viper.New(), p, zap.NewNop())
+	require.NoError(t, err)
+
+	reg := monitor.NewRegistry(nil)
+
+	for name, job := range map[string]monitor.CollectorJob{"main": mainJob, "n3": n3Job} {
+		monitor.RegisterMetrics(reg.With(prometheus.Labels{chainLabel: name}), job.Collectors())
+
+		job.Process(ctx)
+	}
+
+	height := chain.Chain.Chain.GetStateModule().CurrentLocalHeight()
+
+	const expected = `
+# HELP neo_exporter_alphabet_public_key Alphabet public keys in chain
+# TYPE neo_exporter_alphabet_public_key gauge
+neo_exporter_alphabet_public_key{chain="main",key="{committee}"} 1
+# HELP neo_exporter_chain_height Chain height in blocks
+# TYPE neo_exporter_chain_height gauge
+neo_exporter_chain_height{chain="n3",host="{host}"} {height}
+`
+
+	requireExposition(t, reg, expected, map[string]string{
+		"committee": chain.CommitteeKey(t).StringCompressed(),
+		"host":      chain.Address,
+		"height":    strconv.FormatUint(uint64(height), 10),
+	})
+}
+
+func newE2EPool(t *testing.T, chain *chaintest.Chain) *pool.Pool {
+	p, err := pool.NewPool(t.Context(), pool.PrmPool{
+		Endpoints:       []pool.Endpoint{{Address: chain.Address}},
+		DialTimeout:     time.Second,
+		RecheckInterval: time.Hour,
+	})
+	require.NoError(t, err)
+
+	return p
+}
+
+func gasBalance(chain *chaintest.Chain, acc util.Uint160) string {
+	return strconv.FormatFloat(fixedn.Fixed8(chain.Chain.GetUtilityTokenBalance(acc).Int64()).FloatValue(), 'f', -1, 64)
+}
+
+// requireExposition compares metrics of the gatherer with the expected
+// exposition, {name} placeholders are replaced with the values
+// first. Only metrics present in the expected exposition are compared.
+func requireExposition(t *testing.T, gatherer prometheus.Gatherer, expected string, values map[string]string) {
+	var (
+		replace = make([]string, 0, 2*len(values))
+		names   []string
+	)
+
+	for k, v := range values {
+		replace = append(replace, "{"+k+"}", v)
+	}
+
+	expected = strings.NewReplacer(replace...).Replace(expected)
+
+	for line := range strings.Lines(expected) {
+		if name, ok := strings.CutPrefix(line, "# TYPE "); ok {
+			names = append(names, strings.Fields(name)[0])
+		}
+	}
+
+	require.NoError(t, testutil.GatherAndCompare(gatherer, strings.NewReader(expected), names...))
+}
+
+// TestSetupChainE2E sets up the chain from chains section, its metrics
+// including RPC ones are labeled with the chain name, network magic and
+// network name.
+func TestSetupChainE2E(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = t.Context()
+		chain = chaintest.NewMain(t)
+		cfg   = viper.New()
+	)
+
+	DefaultConfiguration(cfg)
+	cfg.SetConfigType("yaml")
+	require.NoError(t, cfg.ReadConfig(strings.NewReader(`
+chains:
+  - name: devnet
+    network: private
+    type: n3
+    rpc:
+      endpoint: `+chain.Address+`
+`)))
+
+	chains, err := parseChains(cfg)
+	require.NoError(t, err)
+	require.Len(t, chains, 1)
+
+	var (
+		health      = monitor.NewHealth(nil)
+		chainHealth = new(chainHealth)
+		mon         = monitor.New(monitor.Args{Logger: zap.NewNop()})
+		reg         = mon.Registry()
+	)
+
+	health.AddChain(chains[0].id(), chainHealth)
+	require.False(t, chainHealth.Healthy())
+
+	c, err := setupChain(ctx, cfg, chains[0], chainHealth, health, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Closer.Close(context.Background()) })
+
+	require.Equal(t, "private/devnet", c.Name)
+	require.True(t, chainHealth.Healthy())
+
+	mon.AddChain(c)
+
+	// Collectors are wrapped, the monitor runs them instead of the job.
+	for _, col := range c.Collectors {
+		_, err = col.Process(ctx)
+		require.NoError(t, err)
+	}
+
+	var (
+		height = chain.Chain.Chain.GetStateModule().CurrentLocalHeight()
+		magic  = chain.Chain.Chain.GetConfig().Magic
+	)
+
+	const expected = `
+# HELP neo_exporter_chain_height Chain height in blocks
+# TYPE neo_exporter_chain_height gauge
+neo_exporter_chain_height{chain="devnet",host="{host}",magic="{magic}",network="private"} {height}
+# HELP neo_exporter_rpc_endpoint_up Whether RPC endpoint is available (1) or not (0)
+# TYPE neo_exporter_rpc_endpoint_up gauge
+neo_exporter_rpc_endpoint_up{chain="devnet",endpoint="{host}",magic="{magic}",network="private"} 1
+`
+
+	requireExposition(t, reg, expected, map[string]string{
+		"host":   chain.Address,
+		"height": strconv.FormatUint(uint64(height), 10),
+		"magic":  strconv.FormatUint(uint64(magic), 10),
+	})
+}
+
+// TestSetupChainsRPCMetricsE2E sets up several chains in one monitor, RPC
+// metrics of their pools are distinguished by the chain label.
+func TestSetupChainsRPCMetricsE2E(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = t.Context()
+		chain = chaintest.New(t)
+		cfg   = viper.New()
+	)
+
+	DefaultConfiguration(cfg)
+	cfg.SetConfigType("yaml")
+	require.NoError(t, cfg.ReadConfig(strings.NewReader(`
+chains:
+  - name: a
+    type: n3
+    rpc:
+      endpoint: `+chain.Address+`
+  - name: b
+    type: n3
+    rpc:
+      endpoint: `+chain.Address+`
+`)))
+
+	chains, err := parseChains(cfg)
+	require.NoError(t, err)
+	require.Len(t, chains, 2)
+
+	var (
+		health = monitor.NewHealth(nil)
+		mon    = monitor.New(monitor.Args{Logger: zap.NewNop()})
+	)
+
+	for _, c := range chains {
+		chainHealth := new(chainHealth)
+		health.AddChain(c.id(), chainHealth)
+
+		setUp, err := setupChain(ctx, cfg, c, chainHealth, health, zap.NewNop())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = setUp.Closer.Close(context.Background()) })
+
+		mon.AddChain(setUp)
+	}
+
+	const expected = `
+# HELP neo_exporter_rpc_endpoint_active Whether RPC endpoint is currently used for requests (1) or not (0)
+# TYPE neo_exporter_rpc_endpoint_active gauge
+neo_exporter_rpc_endpoint_active{chain="a",endpoint="{host}",magic="{magic}"} 1
+neo_exporter_rpc_endpoint_active{chain="b",endpoint="{host}",magic="{magic}"} 1
+`
+
+	requireExposition(t, mon.Registry(), expected, map[string]string{
+		"host":  chain.Address,
+		"magic": strconv.FormatUint(uint64(chain.Chain.GetConfig().Magic), 10),
+	})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/pkg/pool"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// endpointsFileKey is the key of endpoint list in the endpoints file.
+const endpointsFileKey = "endpoint"
+
+// readEndpointsFile reads RPC endpoints from the file having the same format
+// as chain.rpc.endpoint config section. It returns the file contents to detect
+// its changes.
+func readEndpointsFile(path string) ([]pool.Endpoint, []byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		v   = viper.New()
+		ext = strings.TrimPrefix(filepath.Ext(path), ".")
+	)
+
+	if ext == "" {
+		ext = "yaml"
+	}
+
+	v.SetConfigType(ext)
+
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	endpoints, err := parseEndpoints(v, endpointsFileKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return endpoints, data, nil
+}
+
+// watchEndpointsFile rereads the endpoints file every interval and updates
+// pool endpoints if the file is changed. Configured endpoints are always kept.
+func watchEndpointsFile(ctx context.Context, p *pool.Pool, path string, interval time.Duration, configured []pool.Endpoint, last []byte, logger *zap.Logger) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Warn("can't read RPC endpoints file", zap.String("path", path), zap.Error(err))
+			continue
+		}
+
+		if bytes.Equal(data, last) {
+			continue
+		}
+
+		fromFile, data, err := readEndpointsFile(path)
+		if err != nil {
+			logger.Warn("can't read RPC endpoints file", zap.String("path", path), zap.Error(err))
+			continue
+		}
+
+		endpoints := mergeEndpoints(configured, fromFile)
+		if err := p.SetEndpoints(endpoints); err != nil {
+			logger.Warn("can't update RPC endpoints", zap.String("path", path), zap.Error(err))
+			continue
+		}
+
+		last = data
+
+		logger.Info("RPC endpoints updated", zap.Stringers("endpoints", endpoints))
+	}
+}
+
+// mergeEndpoints appends endpoints from the file to configured ones skipping
+// duplicate addresses, configured endpoints take precedence.
+func mergeEndpoints(configured, fromFile []pool.Endpoint) []pool.Endpoint {
+	var res = slices.Clone(configured)
+
+	for _, ep := range fromFile {
+		if !slices.ContainsFunc(res, func(e pool.Endpoint) bool { return e.Address == ep.Address }) {
+			res = append(res, ep)
+		}
+	}
+
+	return res
+}
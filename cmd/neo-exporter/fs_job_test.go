@@ -0,0 +1,117 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/pkg/monitor"
+	"github.com/nspcc-dev/neo-exporter/pkg/pool"
+	"github.com/nspcc-dev/neo-exporter/pkg/rpctest"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestFSChainJob runs FS chain job against the synthetic RPC responses
+// recorded from the in-process test chain, set rpctest.RecordEnv to record
+// them from another node.
+func TestFSChainJob(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx     = t.Context()
+		address = rpctest.Server(t, "testdata/synthetic/fschain.json")
+	)
+
+	p, err := pool.NewPool(ctx, pool.PrmPool{
+		Endpoints:       []pool.Endpoint{{Address: address}},
+		DialTimeout:     time.Second,
+		RecheckInterval: time.Hour,
+	})
+	require.NoError(t, err)
+
+	job, err := fsChainJob(ctx, viper.New(), p, zap.NewNop())
+	require.NoError(t, err)
+
+	reg := monitor.NewRegistry(nil)
+	monitor.RegisterMetrics(reg, job.Collectors())
+
+	job.Process(ctx)
+
+	// Calls made on close have to be recorded before the fixture is saved.
+	require.NoError(t, p.Close(ctx))
+
+	const expected = `
+# HELP neo_exporter_alphabet_balance_notary Side chain notary balance of alphabet nodes
+# TYPE neo_exporter_alphabet_balance_notary gauge
+neo_exporter_alphabet_balance_notary{key="02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2"} 3
+# HELP neo_exporter_alphabet_public_key Alphabet public keys in chain
+# TYPE neo_exporter_alphabet_public_key gauge
+neo_exporter_alphabet_public_key{key="02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2"} 1
+# HELP neo_exporter_candidate_info Candidate node info
+# TYPE neo_exporter_candidate_info gauge
+neo_exporter_candidate_info{host="10.0.0.1",last_active_epoch="0"} 1
+neo_exporter_candidate_info{host="10.0.0.2",last_active_epoch="0"} 1
+neo_exporter_candidate_info{host="10.0.0.3",last_active_epoch="1"} 1
+# HELP neo_exporter_chain_height Chain height in blocks
+# TYPE neo_exporter_chain_height gauge
+neo_exporter_chain_height{host="{host}"} 28
+# HELP neo_exporter_chain_state Chain state hash in specific height
+# TYPE neo_exporter_chain_state gauge
+neo_exporter_chain_state{hash="26bbf8b2b1663535c0ea5b2fc11a860f7e5b3b588ad9234861c9d0ca8a47226a",host="{host}"} 28
+# HELP neo_exporter_container_objects Number of objects in the container
+# TYPE neo_exporter_container_objects gauge
+neo_exporter_container_objects{container="CtCTERVY12Yvaj5h62c1YcLpWtbbxS21aLM5y8jEFmgs"} 8
+# HELP neo_exporter_container_size Size of container
+# TYPE neo_exporter_container_size gauge
+neo_exporter_container_size{container="CtCTERVY12Yvaj5h62c1YcLpWtbbxS21aLM5y8jEFmgs"} 3072
+# HELP neo_exporter_containers_number Number of available containers
+# TYPE neo_exporter_containers_number gauge
+neo_exporter_containers_number 1
+# HELP neo_exporter_containers_objects Total number of objects in available containers
+# TYPE neo_exporter_containers_objects gauge
+neo_exporter_containers_objects 8
+# HELP neo_exporter_containers_size Total size of available containers
+# TYPE neo_exporter_containers_size gauge
+neo_exporter_containers_size 3072
+# HELP neo_exporter_epoch Epoch number of NeoFS network
+# TYPE neo_exporter_epoch gauge
+neo_exporter_epoch 1
+# HELP neo_exporter_fs_chain_supply FS chain total supply of balance contract
+# TYPE neo_exporter_fs_chain_supply gauge
+neo_exporter_fs_chain_supply 7
+# HELP neo_exporter_ir_balance Side chain GAS amount of inner ring nodes
+# TYPE neo_exporter_ir_balance gauge
+neo_exporter_ir_balance{key="02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2"} 15.5709484
+# HELP neo_exporter_netmap Locations where NeoFS storage nodes are located
+# TYPE neo_exporter_netmap gauge
+neo_exporter_netmap{latitude="55.7500",location="Moskva",longitude="37.6000"} 1
+neo_exporter_netmap{latitude="59.8833",location="Saint Petersburg (ex Leningrad)",longitude="30.2500"} 1
+# HELP neo_exporter_netmap_dropped Amount of nodes that will be dropped from network in the next epoch
+# TYPE neo_exporter_netmap_dropped gauge
+neo_exporter_netmap_dropped 0
+# HELP neo_exporter_netmap_new Amount of nodes that will be added to network in the next epoch
+# TYPE neo_exporter_netmap_new gauge
+neo_exporter_netmap_new 1
+# HELP neo_exporter_proxy_balance Side chain GAS amount of proxy contract
+# TYPE neo_exporter_proxy_balance gauge
+neo_exporter_proxy_balance 10
+# HELP neo_exporter_sn_balance Side chain GAS amount of storage nodes
+# TYPE neo_exporter_sn_balance gauge
+neo_exporter_sn_balance{key="02c94b90d5e08ff0a4522bcc354747d929ddfe4302aa9f54b15ad7828da1838ff9"} 0
+neo_exporter_sn_balance{key="03fcb73eb02b96207362d45ceb2980d8c583d6331d30cbb0ba453663664962d4fb"} 5
+# HELP neo_exporter_sn_balance_notary Side chain notary balance of storage nodes
+# TYPE neo_exporter_sn_balance_notary gauge
+neo_exporter_sn_balance_notary{key="02c94b90d5e08ff0a4522bcc354747d929ddfe4302aa9f54b15ad7828da1838ff9"} 0
+neo_exporter_sn_balance_notary{key="03fcb73eb02b96207362d45ceb2980d8c583d6331d30cbb0ba453663664962d4fb"} 2
+# HELP neo_exporter_sn_capacity Storage node capacity (GB)
+# TYPE neo_exporter_sn_capacity gauge
+neo_exporter_sn_capacity{host="10.0.0.1",key="03fcb73eb02b96207362d45ceb2980d8c583d6331d30cbb0ba453663664962d4fb"} 100
+neo_exporter_sn_capacity{host="10.0.0.2",key="02c94b90d5e08ff0a4522bcc354747d929ddfe4302aa9f54b15ad7828da1838ff9"} 200
+# HELP neo_exporter_sn_capacity_total Storage nodes total capacity (GB)
+# TYPE neo_exporter_sn_capacity_total gauge
+neo_exporter_sn_capacity_total 300
+`
+
+	requireExposition(t, reg, expected, map[string]string{"host": address})
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/pkg/monitor"
+	"github.com/nspcc-dev/neo-exporter/pkg/pool"
+	"github.com/nspcc-dev/neo-exporter/pkg/rpctest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestMainChainJob runs main chain job against the synthetic RPC responses
+// recorded from the in-process test chain, set rpctest.RecordEnv to record
+// them from another node.
+func TestMainChainJob(t *testing.T) {
+	t.Parallel()
+
+	const config = `
+contracts:
+  neofs: 902e0d38da5e513b6d07c1c55b85e77d3dce8063
+nep17:
+  - contract: gas
+    label: GAS
+    totalSupply: true
+    balanceOf:
+      - NgzKezqwkHGjSPrVsf75YFNjQD5tm6CtkB
+  - contract: neo
+    label: NEO
+    balanceOf:
+      - NgzKezqwkHGjSPrVsf75YFNjQD5tm6CtkB
+`
+
+	var ctx = t.Context()
+
+	cfg := viper.New()
+	cfg.SetConfigType("yaml")
+	require.NoError(t, cfg.ReadConfig(strings.NewReader(config)))
+
+	p, err := pool.NewPool(ctx, pool.PrmPool{
+		Endpoints:       []pool.Endpoint{{Address: rpctest.Server(t, "testdata/synthetic/mainchain.json")}},
+		DialTimeout:     time.Second,
+		RecheckInterval: time.Hour,
+	})
+	require.NoError(t, err)
+
+	job, err := mainChainJob(ctx, cfg, p, zap.NewNop())
+	require.NoError(t, err)
+
+	reg := monitor.NewRegistry(nil)
+	monitor.RegisterMetrics(reg, job.Collectors())
+
+	job.Process(ctx)
+
+	const expected = `
+# HELP neo_exporter_alphabet_balance Main chain GAS amount of alphabet nodes
+# TYPE neo_exporter_alphabet_balance gauge
+neo_exporter_alphabet_balance{key="02550f471003f3df97c3df506ac797f6721fb1a1fb7b8f6f83d224498a65c88e24"} 200
+neo_exporter_alphabet_balance{key="02591ab771ebbcfd6d9cb9094d106528add1a69d44c2c1f627f089ec58b9c61adf"} 300
+neo_exporter_alphabet_balance{key="026ff03b949241ce1dadd43519e6960e0a85b41a69a05c328103aa2bce1594ca16"} 100
+# HELP neo_exporter_alphabet_public_key Alphabet public keys in chain
+# TYPE neo_exporter_alphabet_public_key gauge
+neo_exporter_alphabet_public_key{key="02550f471003f3df97c3df506ac797f6721fb1a1fb7b8f6f83d224498a65c88e24"} 1
+neo_exporter_alphabet_public_key{key="02591ab771ebbcfd6d9cb9094d106528add1a69d44c2c1f627f089ec58b9c61adf"} 1
+neo_exporter_alphabet_public_key{key="026ff03b949241ce1dadd43519e6960e0a85b41a69a05c328103aa2bce1594ca16"} 1
+# HELP neo_exporter_main_chain_supply Main chain GAS amount of neofs contract
+# TYPE neo_exporter_main_chain_supply gauge
+neo_exporter_main_chain_supply 100
+# HELP neo_exporter_nep_17_balance NEP-17 balance of contract and account
+# TYPE neo_exporter_nep_17_balance gauge
+neo_exporter_nep_17_balance{account="NgzKezqwkHGjSPrVsf75YFNjQD5tm6CtkB",contract="d2a4cff31913016155e38e474a2c06d08be276cf",symbol="GAS"} 5
+neo_exporter_nep_17_balance{account="NgzKezqwkHGjSPrVsf75YFNjQD5tm6CtkB",contract="ef4073a0f2b305a38ec4050e4d3d28bc40ea63f5",symbol="NEO"} 42
+# HELP neo_exporter_nep_17_total_supply NEP-17 total supply of contract
+# TYPE neo_exporter_nep_17_total_supply gauge
+neo_exporter_nep_17_total_supply{contract="d2a4cff31913016155e38e474a2c06d08be276cf",symbol="GAS"} 5.20000054802094e+07
+`
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"neo_exporter_alphabet_balance",
+		"neo_exporter_alphabet_public_key",
+		"neo_exporter_main_chain_supply",
+		"neo_exporter_nep_17_balance",
+		"neo_exporter_nep_17_total_supply",
+	))
+}
@@ -3,7 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nspcc-dev/neo-exporter/pkg/fschain"
@@ -13,6 +17,8 @@ import (
 	"github.com/nspcc-dev/neo-exporter/pkg/pool"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	rpcnns "github.com/nspcc-dev/neofs-contract/rpc/nns"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -35,26 +41,272 @@ func New(ctx context.Context, cfg *viper.Viper) (*monitor.Monitor, error) {
 
 	zap.ReplaceGlobals(logger)
 
-	fsChainEndpoints := cfg.GetStringSlice(prefix + delimiter + cfgNeoRPCEndpoint)
-	fsChainTimeout := cfg.GetDuration(prefix + delimiter + cfgNeoRPCDialTimeout)
-	fsChainRecheck := cfg.GetDuration(prefix + delimiter + cfgNeoRPCRecheckInterval)
-	sleepTimeout := cfg.GetDuration(prefix + delimiter + cfgNeoRPCPoolConnectionSleepTimeout)
+	chains, err := parseChains(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := cfg.GetString(cfgMetricsMode)
+	if mode != metricsModeBackground && mode != metricsModeScrape {
+		return nil, fmt.Errorf("invalid %q: %q, must be %q or %q", cfgMetricsMode, mode, metricsModeBackground, metricsModeScrape)
+	}
+
+	// Collectors are configured by name for all chains, so only names unknown
+	// to all of them are misspelled.
+	names := monitor.CollectorNames()
+
+	for name := range parseCollectorSwitches(cfg, names) {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("invalid %q configuration: unknown collector %q, available: %s", cfgCollectors,
+				name, strings.Join(names, ", "))
+		}
+	}
+
+	labels, err := parseLabels(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := monitor.NewRegistry(labels)
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
 
 	var (
-		sideNeogoClient *pool.Pool
+		health = monitor.NewHealth(nil)
+		mon    = monitor.New(monitor.Args{
+			MetricAddress: cfg.GetString(cfgMetricsEndpoint),
+			Interval:      cfg.GetDuration(cfgMetricsInterval),
+			EveryBlocks:   cfg.GetUint32(cfgMetricsBlocks),
+			OnScrape:      mode == metricsModeScrape,
+			MaxAge:        cfg.GetDuration(cfgMetricsMaxAge),
+			Registry:      registry,
+			Version:       Version,
+			Health:        health,
+			Logger:        logger,
+
+			ShutdownTimeout: cfg.GetDuration(cfgShutdownTimeout),
+		})
 	)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
+	for _, c := range chains {
+		var (
+			chainLogger = logger
+			chainHealth = new(chainHealth)
+		)
+
+		if c.name != "" {
+			chainLogger = logger.With(zap.String(chainLabel, c.name))
+
+			if c.network != "" {
+				chainLogger = chainLogger.With(zap.String(networkLabel, c.network))
+			}
+		}
+
+		health.AddChain(c.id(), chainHealth)
+
+		// Single chain must be available to start the exporter, chains of
+		// chains section are set up in background, so that unavailable
+		// networks don't affect others.
+		if c.name == "" {
+			chain, err := setupChain(ctx, cfg, c, chainHealth, health, chainLogger)
+			if err != nil {
+				return nil, err
+			}
+
+			mon.AddChain(chain)
+
+			continue
+		}
+
+		go func() {
+			chain, err := setupChain(ctx, cfg, c, chainHealth, health, chainLogger)
+			if err != nil {
+				if ctx.Err() == nil {
+					chainLogger.Error("can't set up chain", zap.Error(err))
+				}
+
+				return
+			}
+
+			chainLogger.Info("chain is set up")
+			mon.AddChain(chain)
+		}()
+	}
+
+	return mon, nil
+}
+
+// setupChain connects to the chain and creates its job with enabled
+// collectors. Metrics of chains from chains section are labeled with the chain
+// name, network magic and network name.
+func setupChain(ctx context.Context, cfg *viper.Viper, c chainConfig, chainHealth *chainHealth, health *monitor.Health, logger *zap.Logger) (chain monitor.Chain, err error) {
+	neogoClient, err := newPool(ctx, c.cfg, logger)
+	if err != nil {
+		return chain, err
+	}
+
+	chainHealth.pool.Store(neogoClient)
+
+	chain = monitor.Chain{
+		Name:    c.id(),
+		Blocks:  neogoClient,
+		Closer:  neogoClient,
+		Metrics: neogoClient.Metrics(),
+	}
+
+	var job monitor.CollectorJob
+
+	// The pool is closed by the monitor, but only once the chain is added.
+	defer func() {
+		if err != nil {
+			_ = neogoClient.Close(context.WithoutCancel(ctx))
+		}
+	}()
+
+	if c.name != "" {
+		magic, err := neogoClient.GetNetwork(ctx)
+		if err != nil {
+			return chain, fmt.Errorf("can't get network magic: %w", err)
+		}
+
+		chain.Labels = prometheus.Labels{chainLabel: c.name, magicLabel: strconv.FormatUint(uint64(magic), 10)}
+		if c.network != "" {
+			chain.Labels[networkLabel] = c.network
+		}
+	}
+
+	switch c.typ {
+	case chainTypeFS:
+		var fsJob *monitor.FSJob
+		fsJob, err = fsChainJob(ctx, c.cfg, neogoClient, logger)
+		job, chain.Epochs = fsJob, fsJob
+	case chainTypeN3:
+		job, err = n3ChainJob(ctx, c.cfg, neogoClient, logger)
+	default:
+		job, err = mainChainJob(ctx, c.cfg, neogoClient, logger)
+	}
+
+	if err != nil {
+		return chain, err
+	}
+
+	registry, err := monitor.NewCollectorRegistry(job.Collectors())
+	if err != nil {
+		return chain, err
+	}
+
+	switches := parseCollectorSwitches(cfg, registry.Names())
+	maps.DeleteFunc(switches, func(name string, _ bool) bool {
+		return !slices.Contains(registry.Names(), name)
+	})
+
+	collectors, err := registry.Enabled(switches)
+	if err != nil {
+		return chain, fmt.Errorf("invalid %q configuration: %w", cfgCollectors, err)
+	}
+
+	policies, err := parseStalePolicies(cfg, collectors)
+	if err != nil {
+		return chain, err
+	}
+
+	schedules, err := parseSchedules(cfg, collectors)
+	if err != nil {
+		return chain, err
+	}
+
+	var (
+		onScrape = cfg.GetString(cfgMetricsMode) == metricsModeScrape
+		maxAge   = cfg.GetDuration(cfgMetricsMaxAge)
+	)
+
+	// Collectors are allowed to run or fail for the given number of their
+	// intervals (or max ages on scrape) before the exporter is reported
+	// unhealthy. Collectors scheduled by interval only must also start
+	// within the same time, others run on scrape, new blocks or epoch change.
+	for i, col := range collectors {
+		var (
+			schedule  = schedules[col.Name()]
+			intervals = time.Duration(cfg.GetInt(cfgMetricsHealthIntervals))
+			interval  = schedule.Interval
+		)
+
+		if onScrape {
+			interval = max(interval, maxAge)
 		}
 
-		sideNeogoClient, err = pool.NewPool(ctx, pool.PrmPool{
-			Endpoints:       fsChainEndpoints,
-			DialTimeout:     fsChainTimeout,
-			RecheckInterval: fsChainRecheck,
+		col = monitor.WithStalePolicy(col, policies[col.Name()])
+		col = health.TrackChain(chain.Name, col, intervals*interval)
+
+		if !onScrape && schedule.Blocks == 0 && !schedule.Epoch {
+			health.ExpectRuns(chain.Name, col.Name(), intervals*schedule.Interval)
+		}
+
+		collectors[i] = monitor.Instrument(col)
+	}
+
+	chain.Job, chain.Collectors, chain.Schedules = job, collectors, schedules
+
+	return chain, nil
+}
+
+// newPool creates the RPC pool of the chain configured by chain.rpc section
+// retrying until ctx is done. Endpoints file is watched until ctx is done too.
+func newPool(ctx context.Context, cfg *viper.Viper, logger *zap.Logger) (*pool.Pool, error) {
+	endpoints, err := parseEndpoints(cfg, prefix+delimiter+cfgNeoRPCEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse RPC endpoints: %w", err)
+	}
+
+	var (
+		endpointsFile = cfg.GetString(prefix + delimiter + cfgNeoRPCDiscoveryFile)
+		fileEndpoints []pool.Endpoint
+		fileData      []byte
+	)
+
+	if endpointsFile != "" {
+		fileEndpoints, fileData, err = readEndpointsFile(endpointsFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't read RPC endpoints file: %w", err)
+		}
+	}
+
+	strategy, err := pool.NewStrategy(cfg.GetString(prefix + delimiter + cfgNeoRPCStrategy))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		dialTimeout     = cfg.GetDuration(prefix + delimiter + cfgNeoRPCDialTimeout)
+		recheckInterval = cfg.GetDuration(prefix + delimiter + cfgNeoRPCRecheckInterval)
+		sleepTimeout    = cfg.GetDuration(prefix + delimiter + cfgNeoRPCPoolConnectionSleepTimeout)
+		neogoClient     *pool.Pool
+	)
+
+	for {
+		// The pool is closed by the monitor after the job stops, so it's not
+		// bound to ctx cancelled on shutdown signal.
+		neogoClient, err = pool.NewPool(context.WithoutCancel(ctx), pool.PrmPool{
+			Endpoints:       mergeEndpoints(endpoints, fileEndpoints),
+			DialTimeout:     dialTimeout,
+			RecheckInterval: recheckInterval,
+			MaxHeightLag:    cfg.GetUint32(prefix + delimiter + cfgNeoRPCMaxHeightLag),
+			Strategy:        strategy,
+			CallTimeout:     cfg.GetDuration(prefix + delimiter + cfgNeoRPCCallTimeout),
+			Retry: pool.RetryPolicy{
+				Attempts:   cfg.GetInt(prefix + delimiter + cfgNeoRPCRetryAttempts),
+				MinBackoff: cfg.GetDuration(prefix + delimiter + cfgNeoRPCRetryMinBackoff),
+				MaxBackoff: cfg.GetDuration(prefix + delimiter + cfgNeoRPCRetryMaxBackoff),
+			},
+			Breaker: pool.BreakerPolicy{
+				Threshold: cfg.GetInt(prefix + delimiter + cfgNeoRPCBreakerThreshold),
+				Cooldown:  cfg.GetDuration(prefix + delimiter + cfgNeoRPCBreakerCooldown),
+			},
+			Cache: cfg.GetBool(prefix + delimiter + cfgNeoRPCCache),
+			Discovery: pool.PeerDiscovery{
+				Template: cfg.GetString(prefix + delimiter + cfgNeoRPCDiscoveryPeersTemplate),
+				Priority: cfg.GetInt(prefix + delimiter + cfgNeoRPCDiscoveryPeersPriority),
+				Interval: cfg.GetDuration(prefix + delimiter + cfgNeoRPCDiscoveryPeersInterval),
+			},
 		})
 
 		if err != nil {
@@ -62,41 +314,33 @@ func New(ctx context.Context, cfg *viper.Viper) (*monitor.Monitor, error) {
 				"can't create side chain neo-go client",
 				zap.Error(err),
 				zap.Duration("sleepForSec", sleepTimeout),
-				zap.Strings("endpoints", fsChainEndpoints),
+				zap.Stringers("endpoints", endpoints),
 			)
-			time.Sleep(sleepTimeout)
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(sleepTimeout):
+			}
+
 			continue
 		}
 
 		break
 	}
 
-	var job monitor.Job
-	if cfg.GetBool(cfgChainFSChain) {
-		monitor.RegisterFSChainMetrics()
-		job, err = fsChainJob(cfg, sideNeogoClient, logger)
-	} else {
-		monitor.RegisterMainChainMetrics()
-		job, err = mainChainJob(cfg, sideNeogoClient, logger)
-	}
-	monitor.SetExporterVersion(Version)
-
-	if err != nil {
-		return nil, err
+	if endpointsFile != "" {
+		go watchEndpointsFile(ctx, neogoClient, endpointsFile,
+			cfg.GetDuration(prefix+delimiter+cfgNeoRPCDiscoveryFileInterval), endpoints, fileData, logger)
 	}
 
-	return monitor.New(
-		job,
-		cfg.GetString(cfgMetricsEndpoint),
-		cfg.GetDuration(cfgMetricsInterval),
-		logger,
-	), nil
+	return neogoClient, nil
 }
 
-func mainChainJob(cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*monitor.MainJob, error) {
+func mainChainJob(ctx context.Context, cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*monitor.MainJob, error) {
 	alphabetFetcher := fschain.NewMainChainAlphabetFetcher(neogoClient)
 
-	balanceFetcher, err := monitor.NewNep17BalanceFetcher(neogoClient)
+	balanceFetcher, err := monitor.NewNep17BalanceFetcher(monitor.NewLimitedInvoker(neogoClient, cfg.GetInt(cfgMetricsConcurrency)))
 	if err != nil {
 		return nil, fmt.Errorf("can't initialize Neo chain balance reader: %w", err)
 	}
@@ -119,7 +363,7 @@ func mainChainJob(cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger)
 		return nil, fmt.Errorf("cfg nep17 parse: %w", err)
 	}
 
-	tasks, err := monitor.ParseNep17Tasks(balanceFetcher, items, &contracts.NNSNoOp{})
+	tasks, err := monitor.ParseNep17Tasks(ctx, balanceFetcher, items, &contracts.NNSNoOp{})
 	if err != nil {
 		return nil, err
 	}
@@ -138,13 +382,13 @@ func mainChainJob(cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger)
 	}), nil
 }
 
-func fsChainJob(cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*monitor.FSJob, error) {
-	netmapContract, err := neogoClient.ResolveContract(rpcnns.NameNetmap)
+func fsChainJob(ctx context.Context, cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*monitor.FSJob, error) {
+	netmapContract, err := neogoClient.ResolveContract(ctx, rpcnns.NameNetmap)
 	if err != nil {
 		return nil, fmt.Errorf("can't read netmap scripthash: %w", err)
 	}
 
-	containerContract, err := neogoClient.ResolveContract(rpcnns.NameContainer)
+	containerContract, err := neogoClient.ResolveContract(ctx, rpcnns.NameContainer)
 	if err != nil {
 		return nil, fmt.Errorf("can't read container scripthash: %w", err)
 	}
@@ -165,12 +409,15 @@ func fsChainJob(cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*
 
 	alphabetFetcher := fschain.NewFSChainAlphabetFetcher(neogoClient)
 
-	balanceFetcher, err := monitor.NewNep17BalanceFetcher(neogoClient)
+	// Balance and notary lookups share the limit.
+	invoker := monitor.NewLimitedInvoker(neogoClient, cfg.GetInt(cfgMetricsConcurrency))
+
+	balanceFetcher, err := monitor.NewNep17BalanceFetcher(invoker)
 	if err != nil {
 		return nil, fmt.Errorf("can't initialize side balance fetcher: %w", err)
 	}
 
-	notaryBalanceFetcher, err := monitor.NewNotaryFetcher(neogoClient)
+	notaryBalanceFetcher, err := monitor.NewNotaryFetcher(invoker)
 	if err != nil {
 		return nil, fmt.Errorf("can't initialize notary side balance fetcher: %w", err)
 	}
@@ -180,12 +427,12 @@ func fsChainJob(cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*
 		proxy   *util.Uint160
 	)
 
-	balance, err = neogoClient.ResolveContract(rpcnns.NameBalance)
+	balance, err = neogoClient.ResolveContract(ctx, rpcnns.NameBalance)
 	if err != nil {
 		return nil, fmt.Errorf("balance contract is not available: %w", err)
 	}
 
-	proxyContract, err := neogoClient.ResolveContract(rpcnns.NameProxy)
+	proxyContract, err := neogoClient.ResolveContract(ctx, rpcnns.NameProxy)
 	if err != nil {
 		logger.Info("proxy disabled")
 	} else {
@@ -197,7 +444,7 @@ func fsChainJob(cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*
 		return nil, fmt.Errorf("cfg nep17 parse: %w", err)
 	}
 
-	nnsHash, err := rpcnns.InferHash(neogoClient)
+	nnsHash, err := rpcnns.InferHash(neogoClient.Invoker(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("can't read nns scripthash: %w", err)
 	}
@@ -207,7 +454,7 @@ func fsChainJob(cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*
 		return nil, fmt.Errorf("can't initialize nns fetcher: %w", err)
 	}
 
-	tasks, err := monitor.ParseNep17Tasks(balanceFetcher, items, nnsContract)
+	tasks, err := monitor.ParseNep17Tasks(ctx, balanceFetcher, items, nnsContract)
 	if err != nil {
 		return nil, err
 	}
@@ -232,3 +479,32 @@ func fsChainJob(cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*
 		Nep17tracker:         nep17tracker,
 	}), nil
 }
+
+func n3ChainJob(ctx context.Context, cfg *viper.Viper, neogoClient *pool.Pool, logger *zap.Logger) (*monitor.N3Job, error) {
+	balanceFetcher, err := monitor.NewNep17BalanceFetcher(monitor.NewLimitedInvoker(neogoClient, cfg.GetInt(cfgMetricsConcurrency)))
+	if err != nil {
+		return nil, fmt.Errorf("can't initialize balance reader: %w", err)
+	}
+
+	var items []model.Nep17Balance
+	if err = cfg.UnmarshalKey("nep17", &items); err != nil {
+		return nil, fmt.Errorf("cfg nep17 parse: %w", err)
+	}
+
+	tasks, err := monitor.ParseNep17Tasks(ctx, balanceFetcher, items, &contracts.NNSNoOp{})
+	if err != nil {
+		return nil, err
+	}
+
+	nep17tracker, err := monitor.NewNep17tracker(balanceFetcher, tasks)
+	if err != nil {
+		return nil, fmt.Errorf("nep17tracker: %w", err)
+	}
+
+	return monitor.NewN3Job(monitor.N3JobArgs{
+		Logger:        logger,
+		HeightFetcher: neogoClient,
+		StateFetcher:  neogoClient,
+		Nep17tracker:  nep17tracker,
+	}), nil
+}
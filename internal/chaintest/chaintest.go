@@ -0,0 +1,110 @@
+// Package chaintest runs an in-process Neo chain with NeoFS contracts deployed
+// and serves its JSON-RPC API, so that exporter jobs can be tested end-to-end
+// against the real contracts.
+package chaintest
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// notaryDepositLifetime is the number of blocks notary deposits made by
+// [Chain.DepositNotary] are locked for.
+const notaryDepositLifetime = 1000
+
+// Chain is the single-node chain serving JSON-RPC API. Blocks are produced
+// only when transactions are sent via the embedded executor.
+type Chain struct {
+	*neotest.Executor
+
+	// Address is the URL of the JSON-RPC server.
+	Address string
+}
+
+// New starts the chain with the committee designated to the NeoFS Alphabet
+// role and its JSON-RPC server listening on the random local port. Both are
+// stopped when the test finishes.
+func New(t testing.TB) *Chain {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	cfg := config.Config{
+		ProtocolConfiguration: bc.GetConfig().ProtocolConfiguration,
+		ApplicationConfiguration: config.ApplicationConfiguration{
+			// Node port is reported via getversion, so P2P server must have
+			// an address even though it's never started.
+			P2P: config.P2P{Addresses: []string{"127.0.0.1:0"}},
+			RPC: config.RPC{
+				BasicService: config.BasicService{
+					Enabled:   true,
+					Addresses: []string{"127.0.0.1:0"},
+				},
+				MaxGasInvoke:   fixedn.Fixed8FromInt64(100),
+				SessionEnabled: true,
+			},
+		},
+	}
+
+	srvCfg, err := network.NewServerConfig(cfg)
+	require.NoError(t, err)
+
+	netSrv, err := network.NewServer(srvCfg, bc, bc.GetStateSyncModule(), zap.NewNop())
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+	rpcSrv := rpcsrv.New(bc, cfg.ApplicationConfiguration.RPC, netSrv, nil, zap.NewNop(), errCh)
+	rpcSrv.Start()
+	t.Cleanup(rpcSrv.Shutdown)
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("start RPC server: %v", err)
+	default:
+	}
+
+	c := &Chain{
+		Executor: e,
+		Address:  "http://" + rpcSrv.Addresses()[0],
+	}
+
+	c.CommitteeInvoker(nativehashes.RoleManagement).Invoke(t, stackitem.Null{}, "designateAsRole",
+		int64(noderoles.NeoFSAlphabet), []any{c.CommitteeKey(t).Bytes()})
+
+	return c
+}
+
+// CommitteeKey returns the public key of the only committee member.
+func (c *Chain) CommitteeKey(t testing.TB) *keys.PublicKey {
+	committee, err := c.Chain.GetCommittee()
+	require.NoError(t, err)
+	require.Len(t, committee, 1)
+
+	return committee[0]
+}
+
+// TransferGAS transfers GAS in fractional units from the committee to the
+// account.
+func (c *Chain) TransferGAS(t testing.TB, to util.Uint160, amount int64) {
+	c.CommitteeInvoker(nativehashes.GasToken).Invoke(t, true, "transfer",
+		c.CommitteeHash, to, amount, nil)
+}
+
+// DepositNotary makes the notary deposit in fractional GAS units for the
+// account paying it from the committee.
+func (c *Chain) DepositNotary(t testing.TB, to util.Uint160, amount int64) {
+	c.CommitteeInvoker(nativehashes.GasToken).Invoke(t, true, "transfer",
+		c.CommitteeHash, nativehashes.Notary, amount, []any{to, int64(c.Chain.BlockHeight() + notaryDepositLifetime)})
+}
@@ -0,0 +1,190 @@
+package chaintest
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	fscontracts "github.com/nspcc-dev/neofs-contract/contracts"
+	"github.com/nspcc-dev/neofs-contract/contracts/container/containerconst"
+	"github.com/nspcc-dev/neofs-contract/rpc/nns"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	containertest "github.com/nspcc-dev/neofs-sdk-go/container/test"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/stretchr/testify/require"
+)
+
+// Indexes of contracts in the list returned by [fscontracts.GetFS].
+const (
+	nnsIndex = iota
+	proxyIndex
+	netmapIndex
+	balanceIndex
+	_ // reputation
+	containerIndex
+)
+
+// nodeStateOnline is the netmap contract state of online storage nodes.
+const nodeStateOnline = 1
+
+// FSChain is the FS chain with NNS, proxy, netmap, balance and container
+// contracts deployed and registered in NNS.
+type FSChain struct {
+	*Chain
+
+	NNS       util.Uint160
+	Proxy     util.Uint160
+	Netmap    util.Uint160
+	Balance   util.Uint160
+	Container util.Uint160
+
+	epoch int64
+}
+
+// StorageNode is the storage node added to the network map candidates.
+type StorageNode struct {
+	Key        *keys.PrivateKey
+	Addresses  []string
+	Attributes map[string]string
+}
+
+// NewFS starts FS chain with NeoFS contracts deployed.
+func NewFS(t testing.TB) *FSChain {
+	c := &FSChain{Chain: New(t)}
+
+	cs, err := fscontracts.GetFS()
+	require.NoError(t, err)
+
+	contract := func(i int) *neotest.Contract {
+		return &neotest.Contract{
+			Hash:     state.CreateContractHash(c.Validator.ScriptHash(), cs[i].NEF.Checksum, cs[i].Manifest.Name),
+			NEF:      &cs[i].NEF,
+			Manifest: &cs[i].Manifest,
+		}
+	}
+
+	var (
+		nnsContract       = contract(nnsIndex)
+		proxyContract     = contract(proxyIndex)
+		netmapContract    = contract(netmapIndex)
+		balanceContract   = contract(balanceIndex)
+		containerContract = contract(containerIndex)
+	)
+
+	c.NNS = nnsContract.Hash
+	c.Proxy = proxyContract.Hash
+	c.Netmap = netmapContract.Hash
+	c.Balance = balanceContract.Hash
+	c.Container = containerContract.Hash
+
+	c.DeployContract(t, nnsContract, []any{[]any{[]any{"neofs", "ops@nspcc.ru"}}})
+
+	// Contracts resolve each other via NNS on deployment, so all records are
+	// made beforehand.
+	for name, h := range map[string]util.Uint160{
+		nns.NameProxy:     c.Proxy,
+		nns.NameNetmap:    c.Netmap,
+		nns.NameBalance:   c.Balance,
+		nns.NameContainer: c.Container,
+	} {
+		c.registerContract(t, name, h)
+	}
+
+	c.DeployContract(t, proxyContract, []any{})
+	// Containers are free, but the fees must be configured anyway.
+	c.DeployContract(t, netmapContract, []any{false, util.Uint160{}, util.Uint160{},
+		[]any{c.CommitteeKey(t).Bytes()}, []any{
+			containerconst.RegistrationFeeKey, int64(0),
+			containerconst.AliasFeeKey, int64(0),
+		}})
+	c.DeployContract(t, balanceContract, []any{false, c.Netmap, c.Container})
+	c.DeployContract(t, containerContract, []any{false, c.Netmap, c.Balance, util.Uint160{}, c.NNS})
+
+	return c
+}
+
+func (c *FSChain) registerContract(t testing.TB, name string, h util.Uint160) {
+	inv := c.CommitteeInvoker(c.NNS)
+	domain := name + "." + nns.ContractTLD
+
+	inv.Invoke(t, true, "register", domain, c.CommitteeHash, "ops@nspcc.ru",
+		int64(3600), int64(600), int64(10*365*24*3600), int64(3600))
+	inv.Invoke(t, stackitem.Null{}, "addRecord", domain, nns.TXT, h.StringLE())
+}
+
+// AddNode adds the storage node to the network map candidates. It gets to the
+// network map on the next epoch.
+func (c *FSChain) AddNode(t testing.TB, n StorageNode) {
+	attrs := make([]stackitem.MapElement, 0, len(n.Attributes))
+	for _, k := range slices.Sorted(maps.Keys(n.Attributes)) {
+		attrs = append(attrs, stackitem.MapElement{Key: stackitem.Make(k), Value: stackitem.Make(n.Attributes[k])})
+	}
+
+	addrs := make([]stackitem.Item, 0, len(n.Addresses))
+	for _, a := range n.Addresses {
+		addrs = append(addrs, stackitem.Make(a))
+	}
+
+	node := stackitem.NewStruct([]stackitem.Item{
+		stackitem.NewArray(addrs),
+		stackitem.NewMapWithValue(attrs),
+		stackitem.NewByteArray(n.Key.PublicKey().Bytes()),
+		stackitem.Make(nodeStateOnline),
+	})
+
+	c.CommitteeInvoker(c.Netmap).WithSigners(c.Committee, newSigner(n.Key)).Invoke(t, stackitem.Null{}, "addNode", node)
+}
+
+// NewEpoch ticks the epoch and returns its number.
+func (c *FSChain) NewEpoch(t testing.TB) int64 {
+	c.epoch++
+	c.CommitteeInvoker(c.Netmap).Invoke(t, stackitem.Null{}, "newEpoch", c.epoch)
+
+	return c.epoch
+}
+
+// Mint mints balance contract tokens in fractional units to the account.
+func (c *FSChain) Mint(t testing.TB, to util.Uint160, amount int64) {
+	c.CommitteeInvoker(c.Balance).Invoke(t, stackitem.Null{}, "mint", to, amount, []byte{})
+}
+
+// CreateContainer creates the container owned by the account and placed on
+// the given storage nodes.
+func (c *FSChain) CreateContainer(t testing.TB, owner util.Uint160, nodes ...*keys.PrivateKey) cid.ID {
+	cnr := containertest.Container()
+	cnr.SetOwner(user.NewFromScriptHash(owner))
+
+	var (
+		raw = cnr.Marshal()
+		id  = cid.NewFromMarshalledContainer(raw)
+		inv = c.CommitteeInvoker(c.Container)
+	)
+
+	inv.Invoke(t, stackitem.Null{}, "put", raw, make([]byte, 64), make([]byte, 33), []byte{})
+
+	pubs := make([]any, 0, len(nodes))
+	for _, n := range nodes {
+		pubs = append(pubs, n.PublicKey().Bytes())
+	}
+
+	inv.Invoke(t, stackitem.Null{}, "addNextEpochNodes", id[:], int64(0), pubs)
+	inv.Invoke(t, stackitem.Null{}, "commitContainerListUpdate", id[:], []any{int64(len(nodes))})
+
+	return id
+}
+
+// PutReport makes the container usage report from the storage node.
+func (c *FSChain) PutReport(t testing.TB, id cid.ID, node *keys.PrivateKey, size, objects int64) {
+	c.CommitteeInvoker(c.Container).WithSigners(c.Committee, newSigner(node)).Invoke(t, stackitem.Null{}, "putReport",
+		id[:], size, objects, node.PublicKey().Bytes())
+}
+
+func newSigner(key *keys.PrivateKey) neotest.SingleSigner {
+	return neotest.NewSingleSigner(wallet.NewAccountFromPrivateKey(key))
+}
@@ -0,0 +1,46 @@
+package chaintest
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	fscontracts "github.com/nspcc-dev/neofs-contract/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+// MainChain is the main chain with NeoFS contract deployed.
+type MainChain struct {
+	*Chain
+
+	NeoFS util.Uint160
+}
+
+// NewMain starts main chain with NeoFS contract deployed.
+func NewMain(t testing.TB) *MainChain {
+	c := &MainChain{Chain: New(t)}
+
+	cs, err := fscontracts.GetMain()
+	require.NoError(t, err)
+
+	// NeoFS contract goes first, processing one isn't needed.
+	neofs := &neotest.Contract{
+		Hash:     state.CreateContractHash(c.Validator.ScriptHash(), cs[0].NEF.Checksum, cs[0].Manifest.Name),
+		NEF:      &cs[0].NEF,
+		Manifest: &cs[0].Manifest,
+	}
+
+	c.NeoFS = neofs.Hash
+	c.DeployContract(t, neofs, []any{false, util.Uint160{}, []any{c.CommitteeKey(t).Bytes()}, []any{}})
+
+	return c
+}
+
+// Deposit transfers GAS in fractional units from the committee to NeoFS
+// contract.
+func (c *MainChain) Deposit(t testing.TB, amount int64) {
+	c.CommitteeInvoker(nativehashes.GasToken).Invoke(t, true, "transfer",
+		c.CommitteeHash, c.NeoFS, amount, c.CommitteeHash)
+}
@@ -1,6 +1,7 @@
 package contracts
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/nspcc-dev/neo-exporter/pkg/monitor"
@@ -12,22 +13,20 @@ import (
 )
 
 type Container struct {
-	pool           *pool.Pool
-	contractHash   util.Uint160
-	contractReader *container.ContractReader
+	pool         *pool.Pool
+	contractHash util.Uint160
 }
 
 // NewContainer creates Container to interact with 'container' contract in morph chain.
 func NewContainer(p *pool.Pool, contractHash util.Uint160) (*Container, error) {
 	return &Container{
-		pool:           p,
-		contractHash:   contractHash,
-		contractReader: container.NewReader(p, contractHash),
+		pool:         p,
+		contractHash: contractHash,
 	}, nil
 }
 
-func (c *Container) Total() (int64, error) {
-	amount, err := c.contractReader.Count()
+func (c *Container) Total(ctx context.Context) (int64, error) {
+	amount, err := container.NewReader(c.pool.Invoker(ctx), c.contractHash).Count()
 	if err != nil {
 		return 0, fmt.Errorf("count: %w", err)
 	}
@@ -36,59 +35,41 @@ func (c *Container) Total() (int64, error) {
 }
 
 // NodeReportSummaries returns summary info about containers.
-func (c *Container) NodeReportSummaries() ([]monitor.ContainerInfo, error) {
-	inv, err := c.pool.GetIteratorInvoker()
+func (c *Container) NodeReportSummaries(ctx context.Context) ([]monitor.ContainerInfo, error) {
+	items, err := c.pool.Iterate(ctx, c.contractHash, "iterateAllReportSummaries")
 	if err != nil {
-		return nil, fmt.Errorf("failed to get invoker: %w", err)
+		return nil, fmt.Errorf("can't fetch report summaries: %w", err)
 	}
 
-	contractReader := container.NewReader(inv, c.contractHash)
+	var summaries = make([]monitor.ContainerInfo, 0, len(items))
 
-	sid, iter, err := contractReader.IterateAllReportSummaries()
-	if err != nil {
-		return nil, fmt.Errorf("can't fetch netmap summaries: %w", err)
-	}
-
-	var summaries []monitor.ContainerInfo
-
-	for {
-		items, err := inv.TraverseIterator(sid, &iter, defaultIteratorPage)
+	for _, e := range items {
+		kv := e.Value().([]stackitem.Item)
+		cID, err := kv[0].TryBytes()
 		if err != nil {
-			return nil, fmt.Errorf("can't iterate report summaries: %w", err)
+			return nil, err
 		}
 
-		if len(items) == 0 {
-			break
+		cnrID, err := cid.DecodeBytes(cID)
+		if err != nil {
+			return nil, err
 		}
 
-		for _, e := range items {
-			kv := e.Value().([]stackitem.Item)
-			cID, err := kv[0].TryBytes()
-			if err != nil {
-				return nil, err
-			}
-
-			cnrID, err := cid.DecodeBytes(cID)
-			if err != nil {
-				return nil, err
-			}
-
-			v := kv[1].Value().([]stackitem.Item)
-			sz, err := v[0].TryInteger()
-			if err != nil {
-				return nil, err
-			}
-			objs, err := v[1].TryInteger()
-			if err != nil {
-				return nil, err
-			}
-
-			summaries = append(summaries, monitor.ContainerInfo{
-				ID:              cnrID,
-				Size:            sz.Uint64(),
-				NumberOfObjects: objs.Uint64(),
-			})
+		v := kv[1].Value().([]stackitem.Item)
+		sz, err := v[0].TryInteger()
+		if err != nil {
+			return nil, err
 		}
+		objs, err := v[1].TryInteger()
+		if err != nil {
+			return nil, err
+		}
+
+		summaries = append(summaries, monitor.ContainerInfo{
+			ID:              cnrID,
+			Size:            sz.Uint64(),
+			NumberOfObjects: objs.Uint64(),
+		})
 	}
 
 	return summaries, nil
@@ -1,6 +1,7 @@
 package contracts
 
 import (
+	"context"
 	"crypto/elliptic"
 	"encoding/hex"
 	"fmt"
@@ -24,10 +25,9 @@ import (
 
 type (
 	Netmap struct {
-		pool   *pool.Pool
-		logger *zap.Logger
-
-		contractReader *rpcnetmap.ContractReader
+		pool     *pool.Pool
+		logger   *zap.Logger
+		contract util.Uint160
 	}
 
 	NetmapArgs struct {
@@ -40,26 +40,24 @@ type (
 const (
 	grpcScheme    = "grpc"
 	grpcTLSScheme = "grpcs"
-
-	defaultIteratorPage = 100
 )
 
 // NewNetmap creates Netmap to interact with 'netmap' contract in FS chain.
 func NewNetmap(p NetmapArgs) (*Netmap, error) {
 	return &Netmap{
-		pool:           p.Pool,
-		logger:         p.Logger,
-		contractReader: rpcnetmap.NewReader(p.Pool, p.NetmapContract),
+		pool:     p.Pool,
+		logger:   p.Logger,
+		contract: p.NetmapContract,
 	}, nil
 }
 
-func (c *Netmap) FetchNetmap() (monitor.NetmapInfo, error) {
-	epoch, err := c.Epoch()
+func (c *Netmap) FetchNetmap(ctx context.Context) (monitor.NetmapInfo, error) {
+	epoch, err := c.Epoch(ctx)
 	if err != nil {
 		return monitor.NetmapInfo{}, fmt.Errorf("can't fetch epoch number: %w", err)
 	}
 
-	apiNodes, err := c.Netmap()
+	apiNodes, err := c.Netmap(ctx)
 	if err != nil {
 		return monitor.NetmapInfo{}, fmt.Errorf("can't fetch network map: %w", err)
 	}
@@ -82,17 +80,12 @@ func (c *Netmap) FetchNetmap() (monitor.NetmapInfo, error) {
 	}, nil
 }
 
-func (c *Netmap) FetchCandidates() (monitor.NetmapCandidatesInfo, error) {
-	sid, iter, err := c.contractReader.ListCandidates()
+func (c *Netmap) FetchCandidates(ctx context.Context) (monitor.NetmapCandidatesInfo, error) {
+	items, err := c.pool.Iterate(ctx, c.contract, "listCandidates")
 	if err != nil {
 		return monitor.NetmapCandidatesInfo{}, fmt.Errorf("can't fetch netmap candidates: %w", err)
 	}
 
-	items, err := c.pool.TraverseIterator(sid, &iter, defaultIteratorPage)
-	if err != nil {
-		return monitor.NetmapCandidatesInfo{}, fmt.Errorf("can't iterate netmap candidates: %w", err)
-	}
-
 	var candidates = make([]*monitor.CandidateNode, 0, len(items))
 	for _, item := range items {
 		candidate, err := c.parsedCandidateV2(item)
@@ -108,16 +101,16 @@ func (c *Netmap) FetchCandidates() (monitor.NetmapCandidatesInfo, error) {
 	}, nil
 }
 
-func (c *Netmap) FetchInnerRingKeys() (keys.PublicKeys, error) {
+func (c *Netmap) FetchInnerRingKeys(ctx context.Context) (keys.PublicKeys, error) {
 	var (
 		publicKeys keys.PublicKeys
 		err        error
 		height     uint32
 	)
 
-	height, err = c.pool.GetBlockCount()
+	height, err = c.pool.GetBlockCount(ctx)
 	if err == nil {
-		publicKeys, err = c.pool.GetDesignatedByRole(noderoles.NeoFSAlphabet, height)
+		publicKeys, err = c.pool.GetDesignatedByRole(ctx, noderoles.NeoFSAlphabet, height)
 	}
 
 	if err != nil {
@@ -127,8 +120,8 @@ func (c *Netmap) FetchInnerRingKeys() (keys.PublicKeys, error) {
 	return publicKeys, nil
 }
 
-func (c *Netmap) Epoch() (int64, error) {
-	e, err := c.contractReader.Epoch()
+func (c *Netmap) Epoch(ctx context.Context) (int64, error) {
+	e, err := rpcnetmap.NewReader(c.pool.Invoker(ctx), c.contract).Epoch()
 	if err != nil {
 		return 0, fmt.Errorf("epoch: %w", err)
 	}
@@ -136,15 +129,10 @@ func (c *Netmap) Epoch() (int64, error) {
 	return e.Int64(), nil
 }
 
-func (c *Netmap) Netmap() ([]*netmap.NodeInfo, error) {
-	sid, iter, err := c.contractReader.ListNodes()
-	if err != nil {
-		return nil, fmt.Errorf("can't fetch netmap candidates: %w", err)
-	}
-
-	items, err := c.pool.TraverseIterator(sid, &iter, defaultIteratorPage)
+func (c *Netmap) Netmap(ctx context.Context) ([]*netmap.NodeInfo, error) {
+	items, err := c.pool.Iterate(ctx, c.contract, "listNodes")
 	if err != nil {
-		return nil, fmt.Errorf("can't iterate netmap candidates: %w", err)
+		return nil, fmt.Errorf("can't fetch netmap nodes: %w", err)
 	}
 
 	var candidates = make([]*netmap.NodeInfo, 0, len(items))
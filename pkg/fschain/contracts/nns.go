@@ -1,6 +1,7 @@
 package contracts
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -11,7 +12,8 @@ import (
 
 type (
 	NNS struct {
-		contractReader *nns.ContractReader
+		pool         *pool.Pool
+		contractHash util.Uint160
 	}
 
 	NNSNoOp struct {
@@ -21,12 +23,13 @@ type (
 // NewNNS creates NNS to interact with 'nns' contract in morph chain.
 func NewNNS(p *pool.Pool, contractHash util.Uint160) (*NNS, error) {
 	return &NNS{
-		contractReader: nns.NewReader(p, contractHash),
+		pool:         p,
+		contractHash: contractHash,
 	}, nil
 }
 
-func (c *NNS) ResolveFSContract(name string) (util.Uint160, error) {
-	hash, err := c.contractReader.ResolveFSContract(name)
+func (c *NNS) ResolveFSContract(ctx context.Context, name string) (util.Uint160, error) {
+	hash, err := nns.NewReader(c.pool.Invoker(ctx), c.contractHash).ResolveFSContract(name)
 	if err != nil {
 		return util.Uint160{}, fmt.Errorf("ResolveFSContract: %w", err)
 	}
@@ -34,6 +37,6 @@ func (c *NNS) ResolveFSContract(name string) (util.Uint160, error) {
 	return hash, nil
 }
 
-func (c *NNSNoOp) ResolveFSContract(_ string) (util.Uint160, error) {
+func (c *NNSNoOp) ResolveFSContract(_ context.Context, _ string) (util.Uint160, error) {
 	return util.Uint160{}, errors.New("no op")
 }
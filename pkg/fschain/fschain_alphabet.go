@@ -1,12 +1,14 @@
 package fschain
 
 import (
+	"context"
+
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 )
 
 // Committeer provides FS chain committee public keys.
 type Committeer interface {
-	GetCommittee() (keys.PublicKeys, error)
+	GetCommittee(ctx context.Context) (keys.PublicKeys, error)
 }
 
 type (
@@ -21,6 +23,6 @@ func NewFSChainAlphabetFetcher(committeer Committeer) *FSChainAlphabetFetcher {
 	}
 }
 
-func (a FSChainAlphabetFetcher) FetchAlphabet() (keys.PublicKeys, error) {
-	return a.committeer.GetCommittee()
+func (a FSChainAlphabetFetcher) FetchAlphabet(ctx context.Context) (keys.PublicKeys, error) {
+	return a.committeer.GetCommittee(ctx)
 }
@@ -1,6 +1,7 @@
 package fschain
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
@@ -9,8 +10,8 @@ import (
 
 // Designater must provide main chain alphabet public keys.
 type Designater interface {
-	GetBlockCount() (uint32, error)
-	GetDesignatedByRole(noderoles.Role, uint32) (keys.PublicKeys, error)
+	GetBlockCount(ctx context.Context) (uint32, error)
+	GetDesignatedByRole(context.Context, noderoles.Role, uint32) (keys.PublicKeys, error)
 }
 
 type (
@@ -25,11 +26,11 @@ func NewMainChainAlphabetFetcher(designater Designater) *MainChainAlphabetFetche
 	}
 }
 
-func (a MainChainAlphabetFetcher) FetchAlphabet() (keys.PublicKeys, error) {
-	height, err := a.designater.GetBlockCount()
+func (a MainChainAlphabetFetcher) FetchAlphabet(ctx context.Context) (keys.PublicKeys, error) {
+	height, err := a.designater.GetBlockCount(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("can't get chain height: %w", err)
 	}
 
-	return a.designater.GetDesignatedByRole(noderoles.NeoFSAlphabet, height)
+	return a.designater.GetDesignatedByRole(ctx, noderoles.NeoFSAlphabet, height)
 }
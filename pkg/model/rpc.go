@@ -0,0 +1,48 @@
+package model
+
+type (
+	// RPCEndpoint describes RPC endpoint configuration. It can be set either as
+	// a plain address string or as a structure.
+	RPCEndpoint struct {
+		Address  string `yaml:"address"`
+		Priority int    `yaml:"priority"`
+		// TLS configures secure connections to the endpoint.
+		TLS RPCTLS `yaml:"tls"`
+		// Headers are added to every request to the endpoint.
+		Headers []RPCHeader `yaml:"headers"`
+		// BearerToken sets bearer authorization header.
+		BearerToken *Secret `yaml:"bearer_token" mapstructure:"bearer_token"`
+		// BasicAuth sets basic authorization header.
+		BasicAuth *RPCBasicAuth `yaml:"basic_auth" mapstructure:"basic_auth"`
+		// Proxy is HTTP(S) or SOCKS5 proxy URL.
+		Proxy string `yaml:"proxy"`
+	}
+
+	// RPCTLS describes TLS settings of RPC endpoint. All fields are paths to
+	// PEM files.
+	RPCTLS struct {
+		CA   string `yaml:"ca"`
+		Cert string `yaml:"cert"`
+		Key  string `yaml:"key"`
+	}
+
+	// RPCHeader describes HTTP header added to RPC requests.
+	RPCHeader struct {
+		Name   string `yaml:"name"`
+		Secret `yaml:",inline" mapstructure:",squash"`
+	}
+
+	// RPCBasicAuth describes basic authorization credentials.
+	RPCBasicAuth struct {
+		Username string `yaml:"username"`
+		Password Secret `yaml:"password"`
+	}
+
+	// Secret is a value set literally, read from the file or from the
+	// environment variable. Only one of the fields must be set.
+	Secret struct {
+		Value string `yaml:"value"`
+		File  string `yaml:"file"`
+		Env   string `yaml:"env"`
+	}
+)
@@ -1,49 +1,68 @@
 package monitor
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/big"
+	"sync"
 
-	"github.com/nspcc-dev/neo-go/pkg/rpcclient/nep17"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 )
 
 type (
+	// Invoker performs test invocations of contract methods and scripts.
+	Invoker interface {
+		Call(ctx context.Context, contract util.Uint160, operation string, params ...any) (*result.Invoke, error)
+		Run(ctx context.Context, script []byte) (*result.Invoke, error)
+	}
+
 	// Nep17Fetcher allows to fetch balances from passed contract and account.
+	// It's safe for concurrent use.
 	Nep17Fetcher struct {
-		cli   nep17.Invoker
+		cli Invoker
+
+		mu    sync.RWMutex
 		cache map[util.Uint160]*big.Float
 	}
 )
 
 // NewNep17BalanceFetcher is a constructor for Nep17Fetcher.
-func NewNep17BalanceFetcher(cli nep17.Invoker) (*Nep17Fetcher, error) {
+func NewNep17BalanceFetcher(cli Invoker) (*Nep17Fetcher, error) {
 	return &Nep17Fetcher{
 		cli:   cli,
 		cache: make(map[util.Uint160]*big.Float),
 	}, nil
 }
 
-func (b *Nep17Fetcher) decimals(tokenHash util.Uint160) (*big.Float, error) {
+func (b *Nep17Fetcher) decimals(ctx context.Context, tokenHash util.Uint160) (*big.Float, error) {
+	b.mu.RLock()
 	res, ok := b.cache[tokenHash]
+	b.mu.RUnlock()
+
 	if ok {
 		return res, nil
 	}
 
-	dec, err := nep17.NewReader(b.cli, tokenHash).Decimals()
+	inv, err := b.cli.Call(ctx, tokenHash, "decimals")
+	dec, err := unwrap.LimitedInt64(inv, err, 0, math.MaxInt32)
 	if err != nil {
 		return nil, err
 	}
 
-	res = big.NewFloat(math.Pow10(dec))
+	res = big.NewFloat(math.Pow10(int(dec)))
+
+	b.mu.Lock()
 	b.cache[tokenHash] = res
+	b.mu.Unlock()
 
 	return res, nil
 }
 
-func (b *Nep17Fetcher) format(tokenHash util.Uint160, balance *big.Int) (float64, error) {
-	multiplier, err := b.decimals(tokenHash)
+func (b *Nep17Fetcher) format(ctx context.Context, tokenHash util.Uint160, balance *big.Int) (float64, error) {
+	multiplier, err := b.decimals(ctx, tokenHash)
 	if err != nil {
 		return 0, err
 	}
@@ -58,13 +77,50 @@ func (b *Nep17Fetcher) format(tokenHash util.Uint160, balance *big.Int) (float64
 }
 
 // Fetch returns the token balance of the given account.
-func (b *Nep17Fetcher) Fetch(tokenHash util.Uint160, account util.Uint160) (float64, error) {
-	balance, err := nep17.NewReader(b.cli, tokenHash).BalanceOf(account)
+func (b *Nep17Fetcher) Fetch(ctx context.Context, tokenHash util.Uint160, account util.Uint160) (float64, error) {
+	balance, err := unwrap.BigInt(b.cli.Call(ctx, tokenHash, "balanceOf", account))
+	if err != nil {
+		return 0, fmt.Errorf("balanceOf: %w", err)
+	}
+
+	res, err := b.format(ctx, tokenHash, balance)
+	if err != nil {
+		return 0, fmt.Errorf("format: %w", err)
+	}
+
+	return res, nil
+}
+
+// FetchMany returns token balances of the given accounts in the same order.
+// Requests are combined into a few RPC invocations.
+func (b *Nep17Fetcher) FetchMany(ctx context.Context, tokenHash util.Uint160, accounts []util.Uint160) []BalanceResult {
+	calls := make([]contractCall, 0, len(accounts))
+	for _, acc := range accounts {
+		calls = append(calls, contractCall{contract: tokenHash, method: "balanceOf", params: []any{acc}})
+	}
+
+	results := make([]BalanceResult, 0, len(accounts))
+	for _, r := range invokeBatch(ctx, b.cli, calls) {
+		var res BalanceResult
+
+		res.Value, res.Err = b.formatItem(ctx, tokenHash, r)
+		results = append(results, res)
+	}
+
+	return results
+}
+
+func (b *Nep17Fetcher) formatItem(ctx context.Context, tokenHash util.Uint160, r callResult) (float64, error) {
+	if r.err != nil {
+		return 0, fmt.Errorf("balanceOf: %w", r.err)
+	}
+
+	balance, err := r.item.TryInteger()
 	if err != nil {
 		return 0, fmt.Errorf("balanceOf: %w", err)
 	}
 
-	res, err := b.format(tokenHash, balance)
+	res, err := b.format(ctx, tokenHash, balance)
 	if err != nil {
 		return 0, fmt.Errorf("format: %w", err)
 	}
@@ -73,13 +129,13 @@ func (b *Nep17Fetcher) Fetch(tokenHash util.Uint160, account util.Uint160) (floa
 }
 
 // FetchTotalSupply returns total token supply currently available.
-func (b *Nep17Fetcher) FetchTotalSupply(tokenHash util.Uint160) (float64, error) {
-	balance, err := nep17.NewReader(b.cli, tokenHash).TotalSupply()
+func (b *Nep17Fetcher) FetchTotalSupply(ctx context.Context, tokenHash util.Uint160) (float64, error) {
+	balance, err := unwrap.BigInt(b.cli.Call(ctx, tokenHash, "totalSupply"))
 	if err != nil {
 		return 0, err
 	}
 
-	res, err := b.format(tokenHash, balance)
+	res, err := b.format(ctx, tokenHash, balance)
 	if err != nil {
 		return 0, fmt.Errorf("format: %w", err)
 	}
@@ -88,8 +144,8 @@ func (b *Nep17Fetcher) FetchTotalSupply(tokenHash util.Uint160) (float64, error)
 }
 
 // Symbol returns a short token identifier.
-func (b *Nep17Fetcher) Symbol(tokenHash util.Uint160) (string, error) {
-	symbol, err := nep17.NewReader(b.cli, tokenHash).Symbol()
+func (b *Nep17Fetcher) Symbol(ctx context.Context, tokenHash util.Uint160) (string, error) {
+	symbol, err := unwrap.PrintableASCIIString(b.cli.Call(ctx, tokenHash, "symbol"))
 	if err != nil {
 		return "", err
 	}
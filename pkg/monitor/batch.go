@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+)
+
+// maxBatchSize is the maximum number of contract calls combined into one
+// invocation script.
+const maxBatchSize = 100
+
+type (
+	contractCall struct {
+		contract util.Uint160
+		method   string
+		params   []any
+	}
+
+	callResult struct {
+		item stackitem.Item
+		err  error
+	}
+)
+
+// invokeBatch performs read-only contract calls combining them into
+// invocation scripts, results are returned in the order of calls. Scripts are
+// invoked in parallel. If the script fails, calls are repeated one by one in
+// parallel to get results of the successful ones.
+func invokeBatch(ctx context.Context, cli Invoker, calls []contractCall) []callResult {
+	var (
+		chunks  = slices.Collect(slices.Chunk(calls, maxBatchSize))
+		results = make([][]callResult, len(chunks))
+	)
+
+	parallel(len(chunks), func(i int) {
+		results[i] = invokeChunk(ctx, cli, chunks[i])
+	})
+
+	return slices.Concat(results...)
+}
+
+func invokeChunk(ctx context.Context, cli Invoker, calls []contractCall) []callResult {
+	results := make([]callResult, len(calls))
+
+	if len(calls) > 1 {
+		script, err := batchScript(calls)
+		if err == nil {
+			res, err := cli.Run(ctx, script)
+			if err != nil {
+				for i := range results {
+					results[i].err = err
+				}
+
+				return results
+			}
+
+			if res.State == vmstate.Halt.String() && len(res.Stack) == len(calls) {
+				for i := range results {
+					results[i].item = res.Stack[i]
+				}
+
+				return results
+			}
+		}
+	}
+
+	parallel(len(calls), func(i int) {
+		c := calls[i]
+		results[i].item, results[i].err = unwrap.Item(cli.Call(ctx, c.contract, c.method, c.params...))
+	})
+
+	return results
+}
+
+// batchScript returns the script making all the calls and leaving their
+// results on the stack.
+func batchScript(calls []contractCall) ([]byte, error) {
+	b := smartcontract.NewBuilder()
+	for _, c := range calls {
+		b.InvokeMethod(c.contract, c.method, c.params...)
+	}
+
+	return b.Script()
+}
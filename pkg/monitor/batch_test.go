@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/stretchr/testify/require"
+)
+
+// testInvoker returns the result prepared for the script on every Run and the
+// call parameter on every Call.
+type testInvoker struct {
+	runs map[string]*result.Invoke
+	err  error
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (t *testInvoker) Call(_ context.Context, _ util.Uint160, _ string, params ...any) (*result.Invoke, error) {
+	t.mu.Lock()
+	t.calls++
+	t.mu.Unlock()
+
+	return halt(params[0].(int)), nil
+}
+
+func (t *testInvoker) Run(_ context.Context, script []byte) (*result.Invoke, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	res, ok := t.runs[string(script)]
+	if !ok {
+		return nil, errors.New("unexpected script")
+	}
+
+	delete(t.runs, string(script))
+
+	return res, nil
+}
+
+func halt(values ...int) *result.Invoke {
+	res := &result.Invoke{State: vmstate.Halt.String()}
+	for _, v := range values {
+		res.Stack = append(res.Stack, stackitem.Make(v))
+	}
+
+	return res
+}
+
+func testCalls(n int) []contractCall {
+	calls := make([]contractCall, 0, n)
+	for i := range n {
+		calls = append(calls, contractCall{method: "balanceOf", params: []any{i}})
+	}
+
+	return calls
+}
+
+func testScript(t *testing.T, calls []contractCall) string {
+	script, err := batchScript(calls)
+	require.NoError(t, err)
+
+	return string(script)
+}
+
+func requireResults(t *testing.T, results []callResult, n int) {
+	require.Len(t, results, n)
+
+	for i, r := range results {
+		require.NoError(t, r.err)
+
+		v, err := r.item.TryInteger()
+		require.NoError(t, err)
+		require.EqualValues(t, i, v.Int64())
+	}
+}
+
+func TestInvokeBatch(t *testing.T) {
+	t.Run("chunks", func(t *testing.T) {
+		var (
+			calls  = testCalls(maxBatchSize + 50)
+			first  = make([]int, maxBatchSize)
+			second = make([]int, 50)
+		)
+
+		for i := range first {
+			first[i] = i
+		}
+		for i := range second {
+			second[i] = maxBatchSize + i
+		}
+
+		cli := &testInvoker{runs: map[string]*result.Invoke{
+			testScript(t, calls[:maxBatchSize]): halt(first...),
+			testScript(t, calls[maxBatchSize:]): halt(second...),
+		}}
+
+		requireResults(t, invokeBatch(context.Background(), cli, calls), len(calls))
+		require.Empty(t, cli.runs)
+		require.Zero(t, cli.calls)
+	})
+
+	t.Run("fault", func(t *testing.T) {
+		calls := testCalls(3)
+		cli := &testInvoker{runs: map[string]*result.Invoke{
+			testScript(t, calls): {State: vmstate.Fault.String()},
+		}}
+
+		requireResults(t, invokeBatch(context.Background(), cli, calls), 3)
+		require.Equal(t, 3, cli.calls)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cli := &testInvoker{err: errors.New("connection refused")}
+
+		results := invokeBatch(context.Background(), cli, testCalls(3))
+		require.Len(t, results, 3)
+		for _, r := range results {
+			require.ErrorIs(t, r.err, cli.err)
+		}
+		require.Zero(t, cli.calls)
+	})
+}
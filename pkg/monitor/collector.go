@@ -0,0 +1,174 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type (
+	// Collector is the named part of the job updating a group of metrics it
+	// owns. Collectors are scheduled and enabled independently of each
+	// other.
+	Collector interface {
+		// Name returns the collector name used in the configuration.
+		Name() string
+		// Process collects the data and updates metrics. It returns the
+		// number of items (nodes, containers, accounts) metrics are updated
+		// for. The error means metrics aren't updated, failures of separate
+		// items (e.g. some balances) are only logged.
+		Process(ctx context.Context) (int, error)
+		// Metrics returns metrics updated by the collector.
+		Metrics() []prometheus.Collector
+	}
+
+	// CollectorJob is the [Job] consisting of independently scheduled
+	// collectors.
+	CollectorJob interface {
+		Job
+		// Collectors returns all collectors of the job. Process runs them
+		// all. Collectors only set series updated by the run, the others
+		// are removed according to [WithStalePolicy].
+		Collectors() []Collector
+	}
+
+	// CollectorRegistry is the set of collectors available by name.
+	CollectorRegistry struct {
+		collectors []Collector
+	}
+
+	// collector is the [Collector] made of a function.
+	collector struct {
+		name    string
+		process func(ctx context.Context) (int, error)
+		metrics []prometheus.Collector
+	}
+)
+
+// Names of collectors.
+const (
+	CollectorNetmap           = "netmap"
+	CollectorSNBalances       = "sn_balances"
+	CollectorInnerRing        = "inner_ring"
+	CollectorAlphabet         = "alphabet"
+	CollectorAlphabetBalances = "alphabet_balances"
+	CollectorProxy            = "proxy"
+	CollectorSupply           = "supply"
+	CollectorContainers       = "containers"
+	CollectorChainState       = "chain_state"
+	CollectorNep17            = "nep17"
+)
+
+// CollectorNames returns names of collectors of all jobs.
+func CollectorNames() []string {
+	return []string{
+		CollectorNetmap,
+		CollectorSNBalances,
+		CollectorInnerRing,
+		CollectorAlphabet,
+		CollectorAlphabetBalances,
+		CollectorProxy,
+		CollectorSupply,
+		CollectorContainers,
+		CollectorChainState,
+		CollectorNep17,
+	}
+}
+
+func newCollector(name string, process func(ctx context.Context) (int, error), metrics ...prometheus.Collector) Collector {
+	return &collector{name: name, process: process, metrics: metrics}
+}
+
+// Name implements [Collector].
+func (c *collector) Name() string {
+	return c.name
+}
+
+// Process implements [Collector].
+func (c *collector) Process(ctx context.Context) (int, error) {
+	return c.process(ctx)
+}
+
+// Metrics implements [Collector].
+func (c *collector) Metrics() []prometheus.Collector {
+	return c.metrics
+}
+
+// processCollectors runs collectors concurrently and waits for them. Errors
+// are logged by collectors themselves.
+func processCollectors(ctx context.Context, collectors []Collector) {
+	parallel(len(collectors), func(i int) {
+		_, _ = collectors[i].Process(ctx)
+	})
+}
+
+// balancesResult returns the number of successful balance requests and the
+// error if all of them failed, failures of separate requests are only logged.
+func balancesResult(asset string, results []BalanceResult) (int, error) {
+	var n int
+
+	for _, r := range results {
+		if r.Err == nil {
+			n++
+		}
+	}
+
+	if n == 0 && len(results) != 0 {
+		return 0, fmt.Errorf("all %d %s balance requests failed: %w", len(results), asset, results[0].Err)
+	}
+
+	return n, nil
+}
+
+// NewCollectorRegistry creates CollectorRegistry of collectors with unique
+// names.
+func NewCollectorRegistry(collectors []Collector) (*CollectorRegistry, error) {
+	for i, c := range collectors {
+		if slices.ContainsFunc(collectors[:i], func(other Collector) bool { return other.Name() == c.Name() }) {
+			return nil, fmt.Errorf("duplicate collector %q", c.Name())
+		}
+	}
+
+	return &CollectorRegistry{collectors: collectors}, nil
+}
+
+// Names returns names of all collectors.
+func (r *CollectorRegistry) Names() []string {
+	names := make([]string, 0, len(r.collectors))
+	for _, c := range r.collectors {
+		names = append(names, c.Name())
+	}
+
+	return names
+}
+
+// Enabled returns collectors enabled by switches, collectors missing in
+// switches are enabled. Switches of unknown collectors are an error since
+// they're most likely misspelled.
+func (r *CollectorRegistry) Enabled(switches map[string]bool) ([]Collector, error) {
+	names := r.Names()
+
+	for name := range switches {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("unknown collector %q, available: %s", name, strings.Join(names, ", "))
+		}
+	}
+
+	var res []Collector
+
+	for _, c := range r.collectors {
+		if enabled, ok := switches[c.Name()]; !ok || enabled {
+			res = append(res, c)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("all collectors are disabled")
+	}
+
+	return res, nil
+}
@@ -0,0 +1,59 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testCollectors(names ...string) []Collector {
+	res := make([]Collector, 0, len(names))
+	for _, name := range names {
+		res = append(res, newCollector(name, func(context.Context) (int, error) { return 0, nil }))
+	}
+
+	return res
+}
+
+func TestCollectorRegistry(t *testing.T) {
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := NewCollectorRegistry(testCollectors(CollectorNetmap, CollectorContainers, CollectorNetmap))
+		require.ErrorContains(t, err, `duplicate collector "netmap"`)
+	})
+
+	r, err := NewCollectorRegistry(testCollectors(CollectorNetmap, CollectorContainers, CollectorNep17))
+	require.NoError(t, err)
+	require.Equal(t, []string{CollectorNetmap, CollectorContainers, CollectorNep17}, r.Names())
+
+	names := func(cs []Collector) []string {
+		res := make([]string, 0, len(cs))
+		for _, c := range cs {
+			res = append(res, c.Name())
+		}
+
+		return res
+	}
+
+	t.Run("all by default", func(t *testing.T) {
+		cs, err := r.Enabled(nil)
+		require.NoError(t, err)
+		require.Equal(t, r.Names(), names(cs))
+	})
+
+	t.Run("switches", func(t *testing.T) {
+		cs, err := r.Enabled(map[string]bool{CollectorNetmap: false, CollectorNep17: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{CollectorContainers, CollectorNep17}, names(cs))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := r.Enabled(map[string]bool{"netmaps": false})
+		require.ErrorContains(t, err, `unknown collector "netmaps"`)
+	})
+
+	t.Run("all disabled", func(t *testing.T) {
+		_, err := r.Enabled(map[string]bool{CollectorNetmap: false, CollectorContainers: false, CollectorNep17: false})
+		require.Error(t, err)
+	})
+}
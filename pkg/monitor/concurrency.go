@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// DefaultConcurrency is the default number of RPC requests made by fetchers
+// at once.
+const DefaultConcurrency = 8
+
+// LimitedInvoker is the [Invoker] making a limited number of requests at once.
+// Fetchers sharing it run lookups in parallel without overloading RPC nodes.
+type LimitedInvoker struct {
+	cli Invoker
+	sem chan struct{}
+}
+
+// NewLimitedInvoker creates LimitedInvoker making at most concurrency
+// requests at once via cli. Non-positive concurrency means
+// [DefaultConcurrency].
+func NewLimitedInvoker(cli Invoker, concurrency int) *LimitedInvoker {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	return &LimitedInvoker{
+		cli: cli,
+		sem: make(chan struct{}, concurrency),
+	}
+}
+
+// Call implements [Invoker].
+func (l *LimitedInvoker) Call(ctx context.Context, contract util.Uint160, operation string, params ...any) (*result.Invoke, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+
+	return l.cli.Call(ctx, contract, operation, params...)
+}
+
+// Run implements [Invoker].
+func (l *LimitedInvoker) Run(ctx context.Context, script []byte) (*result.Invoke, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer l.release()
+
+	return l.cli.Run(ctx, script)
+}
+
+func (l *LimitedInvoker) acquire(ctx context.Context) error {
+	select {
+	case l.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *LimitedInvoker) release() {
+	<-l.sem
+}
+
+// parallel calls f for every index in [0, n) concurrently and waits for all
+// of them to finish. Concurrency of RPC requests made by f is expected to be
+// limited by [LimitedInvoker].
+func parallel(n int, f func(i int)) {
+	if n == 1 {
+		f(0)
+		return
+	}
+
+	var wg sync.WaitGroup
+
+	for i := range n {
+		wg.Go(func() { f(i) })
+	}
+
+	wg.Wait()
+}
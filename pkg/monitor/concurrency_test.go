@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+// slowInvoker answers every call with 10 after a delay and tracks the maximum
+// number of requests in progress.
+type slowInvoker struct {
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (s *slowInvoker) Call(_ context.Context, _ util.Uint160, _ string, _ ...any) (*result.Invoke, error) {
+	s.mu.Lock()
+	s.current++
+	s.max = max(s.max, s.current)
+	s.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	s.mu.Lock()
+	s.current--
+	s.mu.Unlock()
+
+	return halt(10), nil
+}
+
+func (s *slowInvoker) Run(ctx context.Context, _ []byte) (*result.Invoke, error) {
+	return s.Call(ctx, util.Uint160{}, "")
+}
+
+func TestLimitedInvoker(t *testing.T) {
+	t.Run("limit", func(t *testing.T) {
+		var (
+			cli  = new(slowInvoker)
+			inv  = NewLimitedInvoker(cli, 3)
+			errs = make([]error, 20)
+		)
+
+		parallel(len(errs), func(i int) {
+			_, errs[i] = inv.Call(context.Background(), util.Uint160{}, "balanceOf")
+		})
+
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		require.Equal(t, 3, cli.max)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		inv := NewLimitedInvoker(new(slowInvoker), 1)
+		inv.sem <- struct{}{}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := inv.Run(ctx, nil)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestNep17FetcherConcurrent(t *testing.T) {
+	b, err := NewNep17BalanceFetcher(NewLimitedInvoker(new(slowInvoker), 4))
+	require.NoError(t, err)
+
+	var (
+		tokens   = []util.Uint160{{1}, {2}}
+		balances = make([]float64, 10)
+		errs     = make([]error, len(balances))
+	)
+
+	parallel(len(balances), func(i int) {
+		balances[i], errs[i] = b.Fetch(context.Background(), tokens[i%len(tokens)], util.Uint160{})
+	})
+
+	for i := range balances {
+		require.NoError(t, errs[i])
+		require.Equal(t, 10e-10, balances[i])
+	}
+
+	require.Len(t, b.cache, len(tokens))
+}
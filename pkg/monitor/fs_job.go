@@ -1,8 +1,12 @@
 package monitor
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math/big"
 	"strconv"
+	"sync"
 
 	"github.com/nspcc-dev/locode-db/pkg/locodedb"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
@@ -42,6 +46,7 @@ type (
 		alphabetFetcher      AlphabetFetcher
 		balance              util.Uint160
 		nep17tracker         *Nep17tracker
+		metrics              *jobMetrics
 	}
 
 	diffNode struct {
@@ -65,16 +70,16 @@ type (
 	}
 
 	ContainerFetcher interface {
-		Total() (int64, error)
-		NodeReportSummaries() ([]ContainerInfo, error)
+		Total(ctx context.Context) (int64, error)
+		NodeReportSummaries(ctx context.Context) ([]ContainerInfo, error)
 	}
 
 	HeightFetcher interface {
-		FetchHeight() []HeightData
+		FetchHeight(ctx context.Context) []HeightData
 	}
 
 	StateFetcher interface {
-		FetchState(height uint32) []StateData
+		FetchState(ctx context.Context, height uint32) []StateData
 	}
 
 	HeightData struct {
@@ -108,12 +113,13 @@ type (
 	}
 
 	NetmapFetcher interface {
-		FetchNetmap() (NetmapInfo, error)
-		FetchCandidates() (NetmapCandidatesInfo, error)
+		EpochFetcher
+		FetchNetmap(ctx context.Context) (NetmapInfo, error)
+		FetchCandidates(ctx context.Context) (NetmapCandidatesInfo, error)
 	}
 
 	InnerRingFetcher interface {
-		FetchInnerRingKeys() (keys.PublicKeys, error)
+		FetchInnerRingKeys(ctx context.Context) (keys.PublicKeys, error)
 	}
 )
 
@@ -131,78 +137,146 @@ func NewFSJob(args FSJobArgs) *FSJob {
 		alphabetFetcher:      args.AlphabetFetcher,
 		balance:              args.Balance,
 		nep17tracker:         args.Nep17tracker,
+		metrics:              newJobMetrics(),
 	}
 }
 
-func (m *FSJob) Process() {
+func (m *FSJob) Process(ctx context.Context) {
 	m.logger.Debug("retrieving data from FS chain")
 
-	netmap, err := m.nmFetcher.FetchNetmap()
+	processCollectors(ctx, m.Collectors())
+}
+
+// Collectors implements [CollectorJob].
+func (m *FSJob) Collectors() []Collector {
+	metrics := m.metrics
+
+	collectors := []Collector{
+		newCollector(CollectorNetmap, m.processNetmap, metrics.locationPresent, metrics.droppedNodesCount,
+			metrics.newNodesCount, metrics.epochNumber, metrics.candidateInfo, metrics.storageNodeCapacity,
+			metrics.storageNodeTotalCapacity),
+		newCollector(CollectorSNBalances, m.processStorageNodeBalances, metrics.storageNodeGASBalances,
+			metrics.storageNodeNotaryBalances),
+		newCollector(CollectorInnerRing, m.processInnerRingKeys, metrics.innerRingBalances),
+		newCollector(CollectorAlphabet, m.processAlphabet, metrics.alphabetPubKeys),
+		newCollector(CollectorAlphabetBalances, m.processAlphabetBalances, metrics.alphabetNotaryBalances),
+		newCollector(CollectorSupply, m.processFSChainSupply, metrics.fsChainSupply),
+		newCollector(CollectorContainers, m.processContainers, metrics.containersNumber, metrics.containersSize,
+			metrics.containersObjects, metrics.containerSize, metrics.containerObjects),
+		newCollector(CollectorChainState, m.processChain, metrics.chainHeight, metrics.chainState),
+	}
+
+	if m.proxy != nil {
+		collectors = append(collectors, newCollector(CollectorProxy, m.processProxyContract, metrics.proxyBalance))
+	}
+
+	if m.nep17tracker != nil {
+		collectors = append(collectors, newCollector(CollectorNep17, m.processNep17tracker, metrics.nep17tracker,
+			metrics.nep17trackerTotal))
+	}
+
+	return collectors
+}
+
+// Epoch implements [EpochFetcher].
+func (m *FSJob) Epoch(ctx context.Context) (int64, error) {
+	return m.nmFetcher.Epoch(ctx)
+}
+
+func (m *FSJob) processNetmap(ctx context.Context) (int, error) {
+	netmap, err := m.nmFetcher.FetchNetmap(ctx)
 	if err != nil {
 		m.logger.Warn("can't read NeoFS network map", zap.Error(err))
-	} else {
-		candidatesNetmap, err := m.nmFetcher.FetchCandidates()
-		if err != nil {
-			m.logger.Warn("can't read NeoFS network map candidates", zap.Error(err))
-		} else {
-			m.processNetworkMap(netmap, candidatesNetmap)
-		}
+		return 0, fmt.Errorf("network map: %w", err)
 	}
 
-	innerRing, err := m.irFetcher.FetchInnerRingKeys()
+	candidatesNetmap, err := m.nmFetcher.FetchCandidates(ctx)
 	if err != nil {
-		m.logger.Warn("can't read NeoFS Inner Ring members", zap.Error(err))
-	} else {
-		m.processInnerRing(innerRing)
+		m.logger.Warn("can't read NeoFS network map candidates", zap.Error(err))
+		return 0, fmt.Errorf("network map candidates: %w", err)
 	}
 
-	if m.proxy != nil {
-		m.processProxyContract()
+	m.processNetworkMap(netmap, candidatesNetmap)
+
+	return len(netmap.Nodes), nil
+}
+
+func (m *FSJob) processStorageNodeBalances(ctx context.Context) (int, error) {
+	netmap, err := m.nmFetcher.FetchNetmap(ctx)
+	if err != nil {
+		m.logger.Warn("can't read NeoFS network map", zap.Error(err))
+		return 0, fmt.Errorf("network map: %w", err)
 	}
 
-	m.processFSChainSupply()
+	return m.processNetmapBalances(ctx, netmap)
+}
+
+func (m *FSJob) processInnerRingKeys(ctx context.Context) (int, error) {
+	innerRing, err := m.irFetcher.FetchInnerRingKeys(ctx)
+	if err != nil {
+		m.logger.Warn("can't read NeoFS Inner Ring members", zap.Error(err))
+		return 0, fmt.Errorf("inner ring: %w", err)
+	}
+
+	return m.processInnerRing(ctx, innerRing)
+}
 
-	if alphabet, err := m.alphabetFetcher.FetchAlphabet(); err != nil {
+func (m *FSJob) processAlphabet(ctx context.Context) (int, error) {
+	alphabet, err := m.alphabetFetcher.FetchAlphabet(ctx)
+	if err != nil {
 		m.logger.Warn("can't read NeoFS ALphabet members", zap.Error(err))
-	} else {
-		processAlphabetPublicKeys(alphabet)
-		m.processFSAlphabet(alphabet)
+		return 0, fmt.Errorf("alphabet: %w", err)
 	}
 
-	m.processContainersNumber()
-	m.processContainersSizeAndObjects()
+	processAlphabetPublicKeys(m.metrics.alphabetPubKeys, alphabet)
 
-	minHeight := m.processChainHeight()
-	m.processChainState(minHeight)
-	m.processNep17tracker()
+	return len(alphabet), nil
 }
 
-func (m *FSJob) processNep17tracker() {
-	if m.nep17tracker != nil {
-		m.nep17tracker.Process(nep17tracker, nep17trackerTotal)
+func (m *FSJob) processAlphabetBalances(ctx context.Context) (int, error) {
+	alphabet, err := m.alphabetFetcher.FetchAlphabet(ctx)
+	if err != nil {
+		m.logger.Warn("can't read NeoFS ALphabet members", zap.Error(err))
+		return 0, fmt.Errorf("alphabet: %w", err)
 	}
+
+	return m.processFSAlphabet(ctx, alphabet)
+}
+
+func (m *FSJob) processContainers(ctx context.Context) (int, error) {
+	var (
+		wg           sync.WaitGroup
+		n            int
+		errNumber    error
+		errSummaries error
+	)
+
+	wg.Go(func() { errNumber = m.processContainersNumber(ctx) })
+	wg.Go(func() { n, errSummaries = m.processContainersSizeAndObjects(ctx) })
+
+	wg.Wait()
+
+	return n, errors.Join(errNumber, errSummaries)
+}
+
+func (m *FSJob) processChain(ctx context.Context) (int, error) {
+	return processChain(ctx, m.metrics, m.heightFetcher, m.stateFetcher)
+}
+
+func (m *FSJob) processNep17tracker(ctx context.Context) (int, error) {
+	return m.nep17tracker.Process(ctx, m.metrics.nep17tracker, m.metrics.nep17trackerTotal)
 }
 
 func (m *FSJob) processNetworkMap(nm NetmapInfo, candidates NetmapCandidatesInfo) {
 	currentNetmapLen := len(nm.Nodes)
 
 	exportCountries := make(map[nodeLocation]int, currentNetmapLen)
-	exportBalancesGAS := make(map[string]float64, currentNetmapLen)
-	exportBalancesNotary := make(map[string]float64, currentNetmapLen)
 
 	newNodes, droppedNodes := getDiff(nm, candidates)
 	var totalCapacity float64
 
 	for _, node := range nm.Nodes {
 		keyHex := node.PublicKey.StringCompressed()
-		scriptHash := node.PublicKey.GetScriptHash()
-
-		balanceGAS, err := m.balanceFetcher.Fetch(gas.Hash, scriptHash)
-		if err != nil {
-			m.logger.Debug("can't fetch GAS balance", zap.String("key", keyHex), zap.Error(err))
-		} else {
-			exportBalancesGAS[keyHex] = balanceGAS
-		}
 
 		record, err := locodedb.Get(node.Locode)
 		if err != nil {
@@ -221,61 +295,97 @@ func (m *FSJob) processNetworkMap(nm NetmapInfo, candidates NetmapCandidatesInfo
 			exportCountries[nodeLoc]++
 		}
 
-		balanceNotary, err := m.notaryBalanceFetcher.FetchNotary(scriptHash)
-		if err != nil {
-			m.logger.Debug("can't fetch notary balance of node from the NeoFS network map",
-				zap.String("key", keyHex),
-				zap.Error(err),
-			)
-		} else {
-			exportBalancesNotary[keyHex] = balanceNotary
-		}
-
 		capacity := float64(node.Capacity)
 		totalCapacity += capacity
 
-		storageNodeCapacity.With(prometheus.Labels{
+		m.metrics.storageNodeCapacity.With(prometheus.Labels{
 			"host": node.Address,
 			"key":  keyHex,
 		}).Set(capacity)
 	}
 
-	storageNodeTotalCapacity.Set(totalCapacity)
+	m.metrics.storageNodeTotalCapacity.Set(totalCapacity)
 
 	m.logNodes("new node", newNodes)
 	m.logNodes("dropped node", droppedNodes)
 
-	epochNumber.Set(float64(nm.Epoch))
-	droppedNodesCount.Set(float64(len(droppedNodes)))
-	newNodesCount.Set(float64(len(newNodes)))
+	m.metrics.epochNumber.Set(float64(nm.Epoch))
+	m.metrics.droppedNodesCount.Set(float64(len(droppedNodes)))
+	m.metrics.newNodesCount.Set(float64(len(newNodes)))
 
-	locationPresent.Reset()
 	for k, v := range exportCountries {
-		locationPresent.With(prometheus.Labels{
+		m.metrics.locationPresent.With(prometheus.Labels{
 			location:  k.name,
 			longitude: k.long,
 			latitude:  k.lat,
 		}).Set(float64(v))
 	}
 
-	storageNodeGASBalances.Reset()
-	for k, v := range exportBalancesGAS {
-		storageNodeGASBalances.WithLabelValues(k).Set(v)
+	for _, candidate := range candidates.Nodes {
+		if candidate.LastEpoch == nil {
+			continue
+		}
+
+		m.metrics.candidateInfo.WithLabelValues(candidate.Address, strconv.FormatUint(candidate.LastEpoch.Uint64(), 10)).Set(1)
 	}
+}
 
-	storageNodeNotaryBalances.Reset()
-	for k, v := range exportBalancesNotary {
-		storageNodeNotaryBalances.WithLabelValues(k).Set(v)
+func (m *FSJob) processNetmapBalances(ctx context.Context, nm NetmapInfo) (int, error) {
+	exportBalancesGAS := make(map[string]float64, len(nm.Nodes))
+	exportBalancesNotary := make(map[string]float64, len(nm.Nodes))
+
+	scriptHashes := make([]util.Uint160, 0, len(nm.Nodes))
+	for _, node := range nm.Nodes {
+		scriptHashes = append(scriptHashes, node.PublicKey.GetScriptHash())
 	}
 
-	candidateInfo.Reset()
-	for _, candidate := range candidates.Nodes {
-		if candidate.LastEpoch == nil {
-			continue
+	var (
+		balancesGAS    []BalanceResult
+		balancesNotary []BalanceResult
+		wg             sync.WaitGroup
+	)
+
+	wg.Go(func() { balancesGAS = m.balanceFetcher.FetchMany(ctx, gas.Hash, scriptHashes) })
+	wg.Go(func() { balancesNotary = m.notaryBalanceFetcher.FetchNotaryMany(ctx, scriptHashes) })
+	wg.Wait()
+
+	var updated int
+
+	for i, node := range nm.Nodes {
+		keyHex := node.PublicKey.StringCompressed()
+
+		if balancesGAS[i].Err == nil || balancesNotary[i].Err == nil {
+			updated++
+		}
+
+		if err := balancesGAS[i].Err; err != nil {
+			m.logger.Debug("can't fetch GAS balance", zap.String("key", keyHex), zap.Error(err))
+		} else {
+			exportBalancesGAS[keyHex] = balancesGAS[i].Value
+		}
+
+		if err := balancesNotary[i].Err; err != nil {
+			m.logger.Debug("can't fetch notary balance of node from the NeoFS network map",
+				zap.String("key", keyHex),
+				zap.Error(err),
+			)
+		} else {
+			exportBalancesNotary[keyHex] = balancesNotary[i].Value
 		}
+	}
+
+	for k, v := range exportBalancesGAS {
+		m.metrics.storageNodeGASBalances.WithLabelValues(k).Set(v)
+	}
 
-		candidateInfo.WithLabelValues(candidate.Address, strconv.FormatUint(candidate.LastEpoch.Uint64(), 10)).Set(1)
+	for k, v := range exportBalancesNotary {
+		m.metrics.storageNodeNotaryBalances.WithLabelValues(k).Set(v)
 	}
+
+	_, errGAS := balancesResult("GAS", balancesGAS)
+	_, errNotary := balancesResult("notary", balancesNotary)
+
+	return updated, errors.Join(errGAS, errNotary)
 }
 
 func (m *FSJob) logNodes(msg string, nodes []*Node) {
@@ -292,14 +402,15 @@ func (m *FSJob) logNodes(msg string, nodes []*Node) {
 	}
 }
 
-func (m *FSJob) processInnerRing(ir keys.PublicKeys) {
+func (m *FSJob) processInnerRing(ctx context.Context, ir keys.PublicKeys) (int, error) {
 	exportBalances := make(map[string]float64, len(ir))
 
-	for _, key := range ir {
+	balances := m.balanceFetcher.FetchMany(ctx, gas.Hash, scriptHashes(ir))
+
+	for i, key := range ir {
 		keyHex := key.StringCompressed()
 
-		balance, err := m.balanceFetcher.Fetch(gas.Hash, key.GetScriptHash())
-		if err != nil {
+		if err := balances[i].Err; err != nil {
 			m.logger.Debug("can't fetch GAS balance of the NeoFS Inner Ring member",
 				zap.String("key", keyHex),
 				zap.Error(err),
@@ -307,70 +418,79 @@ func (m *FSJob) processInnerRing(ir keys.PublicKeys) {
 			continue
 		}
 
-		exportBalances[keyHex] = balance
+		exportBalances[keyHex] = balances[i].Value
 	}
 
-	innerRingBalances.Reset()
 	for k, v := range exportBalances {
-		innerRingBalances.WithLabelValues(k).Set(v)
+		m.metrics.innerRingBalances.WithLabelValues(k).Set(v)
 	}
+
+	return balancesResult("GAS", balances)
 }
 
-func (m *FSJob) processProxyContract() {
-	balance, err := m.balanceFetcher.Fetch(gas.Hash, *m.proxy)
+func (m *FSJob) processProxyContract(ctx context.Context) (int, error) {
+	balance, err := m.balanceFetcher.Fetch(ctx, gas.Hash, *m.proxy)
 	if err != nil {
 		m.logger.Debug("can't fetch proxy contract balance", zap.Stringer("address", m.proxy), zap.Error(err))
-		return
+		return 0, fmt.Errorf("proxy contract balance: %w", err)
 	}
 
-	proxyBalance.Set(balance)
+	m.metrics.proxyBalance.Set(balance)
+
+	return 1, nil
 }
 
-func (m *FSJob) processFSAlphabet(alphabet keys.PublicKeys) {
+func (m *FSJob) processFSAlphabet(ctx context.Context, alphabet keys.PublicKeys) (int, error) {
 	exportNotaryBalances := make(map[string]float64, len(alphabet))
 
-	for _, key := range alphabet {
+	balances := m.notaryBalanceFetcher.FetchNotaryMany(ctx, scriptHashes(alphabet))
+
+	for i, key := range alphabet {
 		keyHex := key.StringCompressed()
 
-		balanceNotary, err := m.notaryBalanceFetcher.FetchNotary(key.GetScriptHash())
-		if err != nil {
+		if err := balances[i].Err; err != nil {
 			m.logger.Debug("can't fetch notary balance of the NeoFS Alphabet member", zap.String("key", keyHex), zap.Error(err))
 		} else {
-			exportNotaryBalances[keyHex] = balanceNotary
+			exportNotaryBalances[keyHex] = balances[i].Value
 		}
 	}
 
-	alphabetNotaryBalances.Reset()
 	for k, v := range exportNotaryBalances {
-		alphabetNotaryBalances.WithLabelValues(k).Set(v)
+		m.metrics.alphabetNotaryBalances.WithLabelValues(k).Set(v)
 	}
+
+	return balancesResult("notary", balances)
 }
 
-func (m *FSJob) processFSChainSupply() {
-	balance, err := m.balanceFetcher.FetchTotalSupply(m.balance)
+func (m *FSJob) processFSChainSupply(ctx context.Context) (int, error) {
+	balance, err := m.balanceFetcher.FetchTotalSupply(ctx, m.balance)
 	if err != nil {
 		m.logger.Debug("can't fetch balance contract total supply", zap.Stringer("address", m.balance), zap.Error(err))
-		return
+		return 0, fmt.Errorf("balance contract total supply: %w", err)
 	}
 
-	fsChainSupply.Set(balance)
+	m.metrics.fsChainSupply.Set(balance)
+
+	return 1, nil
 }
 
-func (m *FSJob) processContainersNumber() {
-	total, err := m.cnrFetcher.Total()
+func (m *FSJob) processContainersNumber(ctx context.Context) error {
+	total, err := m.cnrFetcher.Total(ctx)
 	if err != nil {
 		m.logger.Warn("can't fetch number of available containers", zap.Error(err))
-		return
+		return fmt.Errorf("number of containers: %w", err)
 	}
 
-	containersNumber.Set(float64(total))
+	m.metrics.containersNumber.Set(float64(total))
+
+	return nil
 }
 
-func (m *FSJob) processContainersSizeAndObjects() {
-	containersInfo, err := m.cnrFetcher.NodeReportSummaries()
+func (m *FSJob) processContainersSizeAndObjects(ctx context.Context) (int, error) {
+	containersInfo, err := m.cnrFetcher.NodeReportSummaries(ctx)
 	if err != nil {
 		m.logger.Warn("can't fetch report summaries", zap.Error(err))
-		return
+		return 0, fmt.Errorf("report summaries: %w", err)
 	}
 
 	var (
@@ -378,56 +498,71 @@ func (m *FSJob) processContainersSizeAndObjects() {
 		objects uint64
 	)
 
-	containerSize.Reset()
-	containerObjects.Reset()
-
 	for _, info := range containersInfo {
 		cnr := info.ID.String()
 
 		size += info.Size
 		objects += info.NumberOfObjects
 
-		containerSize.With(prometheus.Labels{
+		m.metrics.containerSize.With(prometheus.Labels{
 			"container": cnr,
 		}).Set(float64(info.Size))
 
-		containerObjects.With(prometheus.Labels{
+		m.metrics.containerObjects.With(prometheus.Labels{
 			"container": cnr,
 		}).Set(float64(info.NumberOfObjects))
 	}
 
-	containersSize.Set(float64(size))
-	containersObjects.Set(float64(objects))
+	m.metrics.containersSize.Set(float64(size))
+	m.metrics.containersObjects.Set(float64(objects))
+
+	return len(containersInfo), nil
+}
+
+// processChain updates heights of RPC nodes and their states at the minimal
+// height.
+func processChain(ctx context.Context, metrics *jobMetrics, heightFetcher HeightFetcher, stateFetcher StateFetcher) (int, error) {
+	minHeight, err := processChainHeight(ctx, metrics, heightFetcher)
+	if err != nil {
+		return 0, err
+	}
+
+	return processChainState(ctx, metrics, stateFetcher, minHeight)
 }
 
-func (m *FSJob) processChainHeight() uint32 {
+func processChainHeight(ctx context.Context, metrics *jobMetrics, heightFetcher HeightFetcher) (uint32, error) {
 	var minHeight uint32
-	heightData := m.heightFetcher.FetchHeight()
+	heightData := heightFetcher.FetchHeight(ctx)
 
 	for _, d := range heightData {
-		chainHeight.WithLabelValues(d.Host).Set(float64(d.Value))
+		metrics.chainHeight.WithLabelValues(d.Host).Set(float64(d.Value))
 
 		if minHeight == 0 || d.Value < minHeight {
 			minHeight = d.Value
 		}
 	}
 
-	return minHeight
-}
-
-func (m *FSJob) processChainState(height uint32) {
-	if height == 0 {
-		return
+	if minHeight == 0 {
+		return 0, errors.New("chain height is not available")
 	}
 
-	stateData := m.stateFetcher.FetchState(height)
-	chainState.Reset()
+	return minHeight, nil
+}
+
+func processChainState(ctx context.Context, metrics *jobMetrics, stateFetcher StateFetcher, height uint32) (int, error) {
+	stateData := stateFetcher.FetchState(ctx, height)
 
 	h := float64(height)
 
 	for _, d := range stateData {
-		chainState.WithLabelValues(d.Host, d.Value).Set(h)
+		metrics.chainState.WithLabelValues(d.Host, d.Value).Set(h)
 	}
+
+	if len(stateData) == 0 {
+		return 0, errors.New("chain state is not available")
+	}
+
+	return len(stateData), nil
 }
 
 func getDiff(nm NetmapInfo, cand NetmapCandidatesInfo) ([]*Node, []*Node) {
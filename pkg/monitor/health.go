@@ -0,0 +1,308 @@
+package monitor
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// HealthChecker reports whether the chain is available.
+	HealthChecker interface {
+		// Healthy checks whether there is a healthy RPC client.
+		Healthy() bool
+	}
+
+	// Health tracks collector runs and reports exporter liveness and
+	// readiness via HTTP handlers. Collectors of different chains are
+	// tracked separately, the unnamed chain is used by single chain setups.
+	Health struct {
+		mu       sync.Mutex
+		chains   map[string]HealthChecker
+		statuses map[healthKey]*collectorStatus
+	}
+
+	healthKey struct {
+		chain     string
+		collector string
+	}
+
+	collectorStatus struct {
+		// window is the time the collector is allowed to fail or run for.
+		window time.Duration
+		// tracked is the time tracking started, it's used instead of the last
+		// success until the collector succeeds.
+		tracked time.Time
+		// period is the time the collector must start within, zero if it
+		// doesn't run regularly.
+		period time.Duration
+
+		started     time.Time
+		lastStart   time.Time
+		finished    bool
+		lastSuccess time.Time
+		lastErr     error
+	}
+
+	// trackedCollector is the [Collector] reporting runs to [Health].
+	trackedCollector struct {
+		Collector
+
+		key    healthKey
+		health *Health
+	}
+
+	// HealthReport is the body of health and readiness responses.
+	HealthReport struct {
+		Status string `json:"status"`
+		// Chain is false if some chain has no healthy RPC client.
+		// Readiness only.
+		Chain *bool `json:"chain,omitempty"`
+		// Chains reports whether named chains have healthy RPC clients.
+		// Readiness only.
+		Chains map[string]bool `json:"chains,omitempty"`
+		// Failing lists collectors making the exporter unhealthy or not
+		// ready.
+		Failing []CollectorReport `json:"failing,omitempty"`
+	}
+
+	// CollectorReport describes the failing collector.
+	CollectorReport struct {
+		Chain        string     `json:"chain,omitempty"`
+		Collector    string     `json:"collector"`
+		Error        string     `json:"error,omitempty"`
+		LastSuccess  *time.Time `json:"last_success,omitempty"`
+		LastStart    *time.Time `json:"last_start,omitempty"`
+		RunningSince *time.Time `json:"running_since,omitempty"`
+	}
+)
+
+// Health report statuses.
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// NewHealth creates Health. Chain is optional, it's added as the unnamed
+// chain.
+func NewHealth(chain HealthChecker) *Health {
+	h := &Health{
+		chains:   make(map[string]HealthChecker),
+		statuses: make(map[healthKey]*collectorStatus),
+	}
+
+	if chain != nil {
+		h.AddChain("", chain)
+	}
+
+	return h
+}
+
+// AddChain makes readiness depend on availability of the named chain.
+func (h *Health) AddChain(name string, chain HealthChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.chains[name] = chain
+}
+
+// Track returns the collector of the unnamed chain reporting its runs to h,
+// see [Health.TrackChain].
+func (h *Health) Track(c Collector, window time.Duration) Collector {
+	return h.TrackChain("", c, window)
+}
+
+// TrackChain returns the collector of the named chain reporting its runs to
+// h. The collector is considered stuck if its run takes longer than window and
+// failing if its last run failed (or no run has finished yet) and there were
+// no successful runs within window. Zero window disables both checks, it's
+// used by collectors without an interval.
+func (h *Health) TrackChain(chain string, c Collector, window time.Duration) Collector {
+	key := healthKey{chain: chain, collector: c.Name()}
+
+	h.mu.Lock()
+	h.statuses[key] = &collectorStatus{window: window, tracked: time.Now()}
+	h.mu.Unlock()
+
+	return &trackedCollector{Collector: c, key: key, health: h}
+}
+
+// ExpectRuns makes liveness depend on regular runs of the named chain
+// collector tracked by h: the collector is considered stalled if it hasn't
+// started for period, e.g. because its scheduler is stuck. Zero period
+// disables the check.
+func (h *Health) ExpectRuns(chain, collector string, period time.Duration) {
+	h.update(healthKey{chain: chain, collector: collector}, func(s *collectorStatus) {
+		s.period = period
+	})
+}
+
+// Process implements [Collector].
+func (c *trackedCollector) Process(ctx context.Context) (int, error) {
+	c.health.update(c.key, func(s *collectorStatus) {
+		s.started = time.Now()
+		s.lastStart = s.started
+	})
+
+	n, err := c.Collector.Process(ctx)
+
+	c.health.update(c.key, func(s *collectorStatus) {
+		s.started = time.Time{}
+		s.finished = true
+		s.lastErr = err
+
+		if err == nil {
+			s.lastSuccess = time.Now()
+		}
+	})
+
+	return n, err
+}
+
+func (h *Health) update(key healthKey, f func(s *collectorStatus)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if s, ok := h.statuses[key]; ok {
+		f(s)
+	}
+}
+
+// Live reports stuck collectors and the ones expected to run regularly that
+// haven't started for their period.
+func (h *Health) Live(now time.Time) HealthReport {
+	return h.report(now, func(s *collectorStatus) bool {
+		if s.window > 0 && !s.started.IsZero() && now.Sub(s.started) > s.window {
+			return true
+		}
+
+		last := s.tracked
+		if s.lastStart.After(last) {
+			last = s.lastStart
+		}
+
+		return s.period > 0 && now.Sub(last) > s.period
+	})
+}
+
+// Ready reports failing collectors and chain availability.
+func (h *Health) Ready(now time.Time) HealthReport {
+	res := h.report(now, func(s *collectorStatus) bool {
+		if s.window <= 0 || s.finished && s.lastErr == nil {
+			return false
+		}
+
+		last := s.tracked
+		if s.lastSuccess.After(last) {
+			last = s.lastSuccess
+		}
+
+		return now.Sub(last) > s.window
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.chains) == 0 {
+		return res
+	}
+
+	all := true
+
+	for name, chain := range h.chains {
+		healthy := chain.Healthy()
+		all = all && healthy
+
+		if name != "" {
+			if res.Chains == nil {
+				res.Chains = make(map[string]bool, len(h.chains))
+			}
+
+			res.Chains[name] = healthy
+		}
+	}
+
+	res.Chain = &all
+
+	if !all {
+		res.Status = HealthStatusFail
+	}
+
+	return res
+}
+
+func (h *Health) report(now time.Time, failing func(s *collectorStatus) bool) HealthReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	res := HealthReport{Status: HealthStatusOK}
+
+	for key, s := range h.statuses {
+		if !failing(s) {
+			continue
+		}
+
+		r := CollectorReport{Chain: key.chain, Collector: key.collector}
+
+		if s.lastErr != nil {
+			r.Error = s.lastErr.Error()
+		} else if !s.finished {
+			r.Error = "no finished runs"
+		}
+
+		if !s.lastSuccess.IsZero() {
+			r.LastSuccess = &s.lastSuccess
+		}
+
+		if !s.lastStart.IsZero() {
+			r.LastStart = &s.lastStart
+		}
+
+		if !s.started.IsZero() {
+			r.RunningSince = &s.started
+		}
+
+		res.Failing = append(res.Failing, r)
+	}
+
+	slices.SortFunc(res.Failing, func(a, b CollectorReport) int {
+		return cmp.Or(strings.Compare(a.Chain, b.Chain), strings.Compare(a.Collector, b.Collector))
+	})
+
+	if len(res.Failing) != 0 {
+		res.Status = HealthStatusFail
+	}
+
+	return res
+}
+
+// LiveHandler serves [Health.Live] reports.
+func (h *Health) LiveHandler() http.Handler {
+	return reportHandler(h.Live)
+}
+
+// ReadyHandler serves [Health.Ready] reports.
+func (h *Health) ReadyHandler() http.Handler {
+	return reportHandler(h.Ready)
+}
+
+// reportHandler responds with the report in JSON, 503 status code is used for
+// failures.
+func reportHandler(report func(now time.Time) HealthReport) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		res := report(time.Now())
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if res.Status != HealthStatusOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_ = json.NewEncoder(w).Encode(res)
+	})
+}
@@ -0,0 +1,195 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testChain bool
+
+func (c *testChain) Healthy() bool {
+	return bool(*c)
+}
+
+func TestHealth(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		chain   = testChain(true)
+		h       = NewHealth(&chain)
+		errTest = errors.New("test")
+		results = make(chan error, 1)
+		release = make(chan struct{})
+		netmap  = h.Track(newCollector(CollectorNetmap, func(context.Context) (int, error) { return 0, <-results }), time.Minute)
+		blocked = h.Track(newCollector(CollectorContainers, func(context.Context) (int, error) {
+			<-release
+			return 0, nil
+		}), time.Minute)
+	)
+
+	requireFailing := func(t *testing.T, r HealthReport, names ...string) {
+		var failing []string
+		for _, c := range r.Failing {
+			failing = append(failing, c.Collector)
+		}
+
+		require.Equal(t, names, failing)
+
+		if len(names) == 0 {
+			require.Equal(t, HealthStatusOK, r.Status)
+		} else {
+			require.Equal(t, HealthStatusFail, r.Status)
+		}
+	}
+
+	t.Run("no runs", func(t *testing.T) {
+		requireFailing(t, h.Ready(time.Now()))
+		requireFailing(t, h.Ready(time.Now().Add(2*time.Minute)), CollectorContainers, CollectorNetmap)
+		requireFailing(t, h.Live(time.Now().Add(2*time.Minute)))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		results <- nil
+		_, err := netmap.Process(ctx)
+		require.NoError(t, err)
+
+		requireFailing(t, h.Ready(time.Now().Add(2*time.Minute)), CollectorContainers)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		results <- errTest
+		_, err := netmap.Process(ctx)
+		require.ErrorIs(t, err, errTest)
+
+		requireFailing(t, h.Ready(time.Now()))
+
+		r := h.Ready(time.Now().Add(2 * time.Minute))
+		requireFailing(t, r, CollectorContainers, CollectorNetmap)
+		require.Equal(t, errTest.Error(), r.Failing[1].Error)
+		require.NotNil(t, r.Failing[1].LastSuccess)
+	})
+
+	t.Run("stuck", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			_, _ = blocked.Process(ctx)
+			close(done)
+		}()
+
+		require.Eventually(t, func() bool {
+			return len(h.Live(time.Now().Add(2*time.Minute)).Failing) == 1
+		}, time.Second, time.Millisecond)
+		requireFailing(t, h.Live(time.Now()))
+
+		close(release)
+		<-done
+
+		requireFailing(t, h.Live(time.Now().Add(2*time.Minute)))
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		results <- nil
+		_, err := netmap.Process(ctx)
+		require.NoError(t, err)
+
+		r := h.Ready(time.Now())
+		requireFailing(t, r)
+		require.True(t, *r.Chain)
+
+		chain = false
+
+		r = h.Ready(time.Now())
+		require.Equal(t, HealthStatusFail, r.Status)
+		require.False(t, *r.Chain)
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		for _, tc := range []struct {
+			handler http.Handler
+			code    int
+			status  string
+		}{
+			{handler: h.LiveHandler(), code: http.StatusOK, status: HealthStatusOK},
+			{handler: h.ReadyHandler(), code: http.StatusServiceUnavailable, status: HealthStatusFail},
+		} {
+			rec := httptest.NewRecorder()
+			tc.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			require.Equal(t, tc.code, rec.Code)
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var r HealthReport
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &r))
+			require.Equal(t, tc.status, r.Status)
+		}
+	})
+
+	t.Run("chains", func(t *testing.T) {
+		var (
+			a, b = testChain(true), testChain(false)
+			h    = NewHealth(nil)
+		)
+
+		h.AddChain("a", &a)
+		h.AddChain("b", &b)
+
+		// Collectors of different chains have the same name.
+		for _, chain := range []string{"b", "a"} {
+			_, err := h.TrackChain(chain, newCollector(CollectorNetmap, func(context.Context) (int, error) {
+				return 0, errTest
+			}), time.Minute).Process(ctx)
+			require.ErrorIs(t, err, errTest)
+		}
+
+		r := h.Ready(time.Now().Add(2 * time.Minute))
+		require.Equal(t, HealthStatusFail, r.Status)
+		require.False(t, *r.Chain)
+		require.Equal(t, map[string]bool{"a": true, "b": false}, r.Chains)
+
+		require.Len(t, r.Failing, 2)
+		require.Equal(t, "a", r.Failing[0].Chain)
+		require.Equal(t, "b", r.Failing[1].Chain)
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		var (
+			h     = NewHealth(nil)
+			proxy = h.Track(newCollector(CollectorProxy, func(context.Context) (int, error) { return 0, nil }), time.Minute)
+		)
+
+		h.Track(newCollector(CollectorNetmap, func(context.Context) (int, error) { return 0, nil }), time.Minute)
+		h.ExpectRuns("", CollectorProxy, time.Minute)
+		// Unknown collectors are ignored.
+		h.ExpectRuns("", CollectorSupply, time.Minute)
+
+		requireFailing(t, h.Live(time.Now()))
+		requireFailing(t, h.Live(time.Now().Add(2*time.Minute)), CollectorProxy)
+
+		_, err := proxy.Process(ctx)
+		require.NoError(t, err)
+
+		requireFailing(t, h.Live(time.Now().Add(30*time.Second)))
+
+		r := h.Live(time.Now().Add(2 * time.Minute))
+		requireFailing(t, r, CollectorProxy)
+		require.NotNil(t, r.Failing[0].LastStart)
+	})
+
+	t.Run("no window", func(t *testing.T) {
+		h := NewHealth(nil)
+
+		_, err := h.Track(newCollector(CollectorNetmap, func(context.Context) (int, error) {
+			return 0, errTest
+		}), 0).Process(ctx)
+		require.ErrorIs(t, err, errTest)
+
+		requireFailing(t, h.Ready(time.Now().Add(time.Hour)))
+		requireFailing(t, h.Live(time.Now().Add(time.Hour)))
+	})
+}
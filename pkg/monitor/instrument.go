@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// instrumentedCollector is the [Collector] exporting metrics of its own runs.
+type instrumentedCollector struct {
+	Collector
+
+	metrics collectorMetrics
+}
+
+// Instrument returns the collector exporting duration, last success time,
+// number of consecutive errors and number of processed items of its runs.
+// These metrics are labeled with the collector name and returned by Metrics
+// along with metrics of c.
+func Instrument(c Collector) Collector {
+	return &instrumentedCollector{Collector: c, metrics: newCollectorMetrics(c.Name())}
+}
+
+// Process implements [Collector].
+func (c *instrumentedCollector) Process(ctx context.Context) (int, error) {
+	start := time.Now()
+
+	n, err := c.Collector.Process(ctx)
+
+	c.metrics.duration.Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		c.metrics.consecutiveErrors.Inc()
+		return n, err
+	}
+
+	c.metrics.consecutiveErrors.Set(0)
+	c.metrics.lastSuccess.Set(float64(time.Now().Unix()))
+	c.metrics.items.Set(float64(n))
+
+	return n, nil
+}
+
+// Metrics implements [Collector].
+func (c *instrumentedCollector) Metrics() []prometheus.Collector {
+	return append(slices.Clip(c.Collector.Metrics()),
+		c.metrics.duration, c.metrics.lastSuccess, c.metrics.consecutiveErrors, c.metrics.items)
+}
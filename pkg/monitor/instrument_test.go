@@ -0,0 +1,66 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrument(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		errTest = errors.New("test")
+		result  error
+		c       = Instrument(newCollector("instrument_test", func(context.Context) (int, error) {
+			if result != nil {
+				return 0, result
+			}
+
+			return 5, nil
+		}))
+	)
+
+	metrics := c.(*instrumentedCollector).metrics
+
+	requireMetrics := func(t *testing.T, errs int, items float64, success bool) {
+		require.EqualValues(t, errs, testutil.ToFloat64(metrics.consecutiveErrors))
+		require.EqualValues(t, items, testutil.ToFloat64(metrics.items))
+
+		if success {
+			require.InDelta(t, float64(time.Now().Unix()), testutil.ToFloat64(metrics.lastSuccess), 1)
+		}
+	}
+
+	requireMetrics(t, 0, 0, false)
+
+	n, err := c.Process(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	requireMetrics(t, 0, 5, true)
+
+	result = errTest
+
+	for i := range 3 {
+		_, err = c.Process(ctx)
+		require.ErrorIs(t, err, errTest)
+		requireMetrics(t, i+1, 5, true)
+	}
+
+	result = nil
+
+	_, err = c.Process(ctx)
+	require.NoError(t, err)
+	requireMetrics(t, 0, 5, true)
+
+	// Run metrics are exported along with collector ones.
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(c.Metrics()...)
+	require.Equal(t, 4, testutil.CollectAndCount(reg, "neo_exporter_collector_duration_seconds",
+		"neo_exporter_collector_last_success_timestamp_seconds", "neo_exporter_collector_consecutive_errors",
+		"neo_exporter_collector_items"))
+}
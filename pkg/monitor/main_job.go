@@ -1,6 +1,9 @@
 package monitor
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/gas"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -22,6 +25,7 @@ type (
 		logger          *zap.Logger
 		neofs           *util.Uint160
 		nep17tracker    *Nep17tracker
+		metrics         *jobMetrics
 	}
 )
 
@@ -32,57 +36,89 @@ func NewMainJob(args MainJobArgs) *MainJob {
 		logger:          args.Logger,
 		neofs:           args.Neofs,
 		nep17tracker:    args.Nep17tracker,
+		metrics:         newJobMetrics(),
 	}
 }
 
-func (m *MainJob) Process() {
-	if mainAlphabet, err := m.alphabetFetcher.FetchAlphabet(); err != nil {
+func (m *MainJob) Process(ctx context.Context) {
+	processCollectors(ctx, m.Collectors())
+}
+
+// Collectors implements [CollectorJob].
+func (m *MainJob) Collectors() []Collector {
+	collectors := []Collector{
+		newCollector(CollectorAlphabet, m.processAlphabet, m.metrics.alphabetPubKeys),
+		newCollector(CollectorAlphabetBalances, m.processAlphabetBalances, m.metrics.alphabetGASBalances),
+	}
+
+	if m.neofs != nil {
+		collectors = append(collectors, newCollector(CollectorSupply, m.processMainChainSupply, m.metrics.mainChainSupply))
+	}
+
+	if m.nep17tracker != nil {
+		collectors = append(collectors, newCollector(CollectorNep17, m.processNep17tracker, m.metrics.nep17tracker,
+			m.metrics.nep17trackerTotal))
+	}
+
+	return collectors
+}
+
+func (m *MainJob) processAlphabet(ctx context.Context) (int, error) {
+	mainAlphabet, err := m.alphabetFetcher.FetchAlphabet(ctx)
+	if err != nil {
 		m.logger.Warn("can't read NeoFS Aphabet members", zap.Error(err))
-	} else {
-		processAlphabetPublicKeys(mainAlphabet)
-		m.processMainAlphabet(mainAlphabet)
+		return 0, fmt.Errorf("alphabet: %w", err)
 	}
 
-	m.processMainChainSupply()
-	m.processNep17tracker()
+	processAlphabetPublicKeys(m.metrics.alphabetPubKeys, mainAlphabet)
+
+	return len(mainAlphabet), nil
 }
 
-func (m *MainJob) processNep17tracker() {
-	if m.nep17tracker != nil {
-		m.nep17tracker.Process(nep17tracker, nep17trackerTotal)
+func (m *MainJob) processAlphabetBalances(ctx context.Context) (int, error) {
+	mainAlphabet, err := m.alphabetFetcher.FetchAlphabet(ctx)
+	if err != nil {
+		m.logger.Warn("can't read NeoFS Aphabet members", zap.Error(err))
+		return 0, fmt.Errorf("alphabet: %w", err)
 	}
+
+	return m.processMainAlphabet(ctx, mainAlphabet)
+}
+
+func (m *MainJob) processNep17tracker(ctx context.Context) (int, error) {
+	return m.nep17tracker.Process(ctx, m.metrics.nep17tracker, m.metrics.nep17trackerTotal)
 }
 
-func (m *MainJob) processMainAlphabet(alphabet keys.PublicKeys) {
+func (m *MainJob) processMainAlphabet(ctx context.Context, alphabet keys.PublicKeys) (int, error) {
 	exportGasBalances := make(map[string]float64, len(alphabet))
 
-	for _, key := range alphabet {
+	balances := m.balanceFetcher.FetchMany(ctx, gas.Hash, scriptHashes(alphabet))
+
+	for i, key := range alphabet {
 		keyHex := key.StringCompressed()
 
-		balanceGAS, err := m.balanceFetcher.Fetch(gas.Hash, key.GetScriptHash())
-		if err != nil {
+		if err := balances[i].Err; err != nil {
 			m.logger.Debug("can't fetch gas balance", zap.String("key", keyHex), zap.Error(err))
 		} else {
-			exportGasBalances[keyHex] = balanceGAS
+			exportGasBalances[keyHex] = balances[i].Value
 		}
 	}
 
-	alphabetGASBalances.Reset()
 	for k, v := range exportGasBalances {
-		alphabetGASBalances.WithLabelValues(k).Set(v)
+		m.metrics.alphabetGASBalances.WithLabelValues(k).Set(v)
 	}
-}
 
-func (m *MainJob) processMainChainSupply() {
-	if m.neofs == nil {
-		return
-	}
+	return balancesResult("GAS", balances)
+}
 
-	balance, err := m.balanceFetcher.Fetch(gas.Hash, *m.neofs)
+func (m *MainJob) processMainChainSupply(ctx context.Context) (int, error) {
+	balance, err := m.balanceFetcher.Fetch(ctx, gas.Hash, *m.neofs)
 	if err != nil {
 		m.logger.Debug("can't fetch NeoFS contract's GAS balance", zap.Error(err))
-		return
+		return 0, fmt.Errorf("NeoFS contract balance: %w", err)
 	}
 
-	mainChainSupply.Set(balance)
+	m.metrics.mainChainSupply.Set(balance)
+
+	return 1, nil
 }
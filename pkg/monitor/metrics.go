@@ -1,6 +1,9 @@
 package monitor
 
 import (
+	"context"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -9,301 +12,377 @@ const (
 	longitude = "longitude"
 	latitude  = "latitude"
 	namespace = "neo_exporter"
+
+	collectorLabel = "collector"
 )
 
-var (
-	binaryVersion = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Help:      "Exporter version",
-			Name:      "version",
-			Namespace: namespace,
-		},
-		[]string{"version"},
-	)
+var staleSeriesDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "stale_series"),
+	"Number of series exported with values from previous collector runs",
+	[]string{collectorLabel, "metric"},
+	nil,
+)
 
-	locationPresent = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "netmap",
-			Help:      "Locations where NeoFS storage nodes are located",
-		},
-		[]string{
-			location,
-			longitude,
-			latitude,
-		},
-	)
+type (
+	// jobMetrics are metrics updated by collectors of a single job.
+	jobMetrics struct {
+		locationPresent           *prometheus.GaugeVec
+		droppedNodesCount         prometheus.Gauge
+		newNodesCount             prometheus.Gauge
+		epochNumber               prometheus.Gauge
+		innerRingBalances         *prometheus.GaugeVec
+		alphabetGASBalances       *prometheus.GaugeVec
+		alphabetNotaryBalances    *prometheus.GaugeVec
+		storageNodeGASBalances    *prometheus.GaugeVec
+		storageNodeNotaryBalances *prometheus.GaugeVec
+		proxyBalance              prometheus.Gauge
+		mainChainSupply           prometheus.Gauge
+		fsChainSupply             prometheus.Gauge
+		alphabetPubKeys           *prometheus.GaugeVec
+		containersNumber          prometheus.Gauge
+		containersSize            prometheus.Gauge
+		containersObjects         prometheus.Gauge
+		containerSize             *prometheus.GaugeVec
+		containerObjects          *prometheus.GaugeVec
+		chainHeight               *prometheus.GaugeVec
+		chainState                *prometheus.GaugeVec
+		nep17tracker              *prometheus.GaugeVec
+		nep17trackerTotal         *prometheus.GaugeVec
+		candidateInfo             *prometheus.GaugeVec
+		storageNodeCapacity       *prometheus.GaugeVec
+		storageNodeTotalCapacity  prometheus.Gauge
+	}
 
-	droppedNodesCount = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "netmap_dropped",
-			Help:      "Amount of nodes that will be dropped from network in the next epoch",
-		},
-	)
+	// collectorMetrics are metrics of runs of a single collector.
+	collectorMetrics struct {
+		duration          prometheus.Histogram
+		lastSuccess       prometheus.Gauge
+		consecutiveErrors prometheus.Gauge
+		items             prometheus.Gauge
+	}
+)
 
-	newNodesCount = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "netmap_new",
-			Help:      "Amount of nodes that will be added to network in the next epoch",
-		},
-	)
+// newJobMetrics creates metrics of a job, they are registered as metrics of
+// its collectors.
+func newJobMetrics() *jobMetrics {
+	return &jobMetrics{
+		locationPresent: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "netmap",
+				Help:      "Locations where NeoFS storage nodes are located",
+			},
+			[]string{
+				location,
+				longitude,
+				latitude,
+			},
+		),
 
-	epochNumber = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "epoch",
-			Help:      "Epoch number of NeoFS network",
-		},
-	)
+		droppedNodesCount: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "netmap_dropped",
+				Help:      "Amount of nodes that will be dropped from network in the next epoch",
+			},
+		),
 
-	innerRingBalances = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "ir_balance",
-			Help:      "Side chain GAS amount of inner ring nodes",
-		},
-		[]string{
-			"key",
-		},
-	)
+		newNodesCount: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "netmap_new",
+				Help:      "Amount of nodes that will be added to network in the next epoch",
+			},
+		),
 
-	alphabetGASBalances = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "alphabet_balance",
-			Help:      "Main chain GAS amount of alphabet nodes",
-		},
-		[]string{
-			"key",
-		},
-	)
+		epochNumber: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "epoch",
+				Help:      "Epoch number of NeoFS network",
+			},
+		),
 
-	alphabetNotaryBalances = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "alphabet_balance_notary",
-			Help:      "Side chain notary balance of alphabet nodes",
-		},
-		[]string{
-			"key",
-		},
-	)
+		innerRingBalances: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "ir_balance",
+				Help:      "Side chain GAS amount of inner ring nodes",
+			},
+			[]string{
+				"key",
+			},
+		),
 
-	storageNodeGASBalances = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "sn_balance",
-			Help:      "Side chain GAS amount of storage nodes",
-		},
-		[]string{
-			"key",
-		},
-	)
+		alphabetGASBalances: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "alphabet_balance",
+				Help:      "Main chain GAS amount of alphabet nodes",
+			},
+			[]string{
+				"key",
+			},
+		),
 
-	storageNodeNotaryBalances = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "sn_balance_notary",
-			Help:      "Side chain notary balance of storage nodes",
-		},
-		[]string{
-			"key",
-		},
-	)
+		alphabetNotaryBalances: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "alphabet_balance_notary",
+				Help:      "Side chain notary balance of alphabet nodes",
+			},
+			[]string{
+				"key",
+			},
+		),
 
-	proxyBalance = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "proxy_balance",
-			Help:      "Side chain GAS amount of proxy contract",
-		},
-	)
+		storageNodeGASBalances: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "sn_balance",
+				Help:      "Side chain GAS amount of storage nodes",
+			},
+			[]string{
+				"key",
+			},
+		),
 
-	mainChainSupply = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "main_chain_supply",
-			Help:      "Main chain GAS amount of neofs contract",
-		},
-	)
+		storageNodeNotaryBalances: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "sn_balance_notary",
+				Help:      "Side chain notary balance of storage nodes",
+			},
+			[]string{
+				"key",
+			},
+		),
 
-	fsChainSupply = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "fs_chain_supply",
-			Help:      "FS chain total supply of balance contract",
-		},
-	)
+		proxyBalance: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "proxy_balance",
+				Help:      "Side chain GAS amount of proxy contract",
+			},
+		),
 
-	alphabetPubKeys = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "alphabet_public_key",
-			Help:      "Alphabet public keys in chain",
-		},
-		[]string{
-			"key",
-		},
-	)
+		mainChainSupply: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "main_chain_supply",
+				Help:      "Main chain GAS amount of neofs contract",
+			},
+		),
 
-	containersNumber = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "containers_number",
-			Help:      "Number of available containers",
-		},
-	)
+		fsChainSupply: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "fs_chain_supply",
+				Help:      "FS chain total supply of balance contract",
+			},
+		),
 
-	containersSize = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "containers_size",
-			Help:      "Total size of available containers",
-		},
-	)
+		alphabetPubKeys: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "alphabet_public_key",
+				Help:      "Alphabet public keys in chain",
+			},
+			[]string{
+				"key",
+			},
+		),
 
-	containersObjects = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "containers_objects",
-			Help:      "Total number of objects in available containers",
-		},
-	)
+		containersNumber: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "containers_number",
+				Help:      "Number of available containers",
+			},
+		),
 
-	containerSize = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "container_size",
-			Help:      "Size of container",
-		},
-		[]string{"container"},
-	)
+		containersSize: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "containers_size",
+				Help:      "Total size of available containers",
+			},
+		),
 
-	containerObjects = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "container_objects",
-			Help:      "Number of objects in the container",
-		},
-		[]string{"container"},
-	)
+		containersObjects: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "containers_objects",
+				Help:      "Total number of objects in available containers",
+			},
+		),
 
-	chainHeight = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "chain_height",
-			Help:      "Chain height in blocks",
-		},
-		[]string{
-			"host",
-		},
-	)
+		containerSize: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "container_size",
+				Help:      "Size of container",
+			},
+			[]string{"container"},
+		),
 
-	chainState = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "chain_state",
-			Help:      "Chain state hash in specific height",
-		},
-		[]string{
-			"host", "hash",
-		},
-	)
+		containerObjects: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "container_objects",
+				Help:      "Number of objects in the container",
+			},
+			[]string{"container"},
+		),
 
-	nep17tracker = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "nep_17_balance",
-			Help:      "NEP-17 balance of contract and account",
-		},
-		[]string{
-			"symbol", "contract", "account",
-		},
-	)
+		chainHeight: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "chain_height",
+				Help:      "Chain height in blocks",
+			},
+			[]string{
+				"host",
+			},
+		),
 
-	nep17trackerTotal = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "nep_17_total_supply",
-			Help:      "NEP-17 total supply of contract",
-		},
-		[]string{
-			"symbol", "contract",
-		},
-	)
+		chainState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "chain_state",
+				Help:      "Chain state hash in specific height",
+			},
+			[]string{
+				"host", "hash",
+			},
+		),
 
-	candidateInfo = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "candidate_info",
-			Help:      "Candidate node info",
-		},
-		[]string{
-			"host", "last_active_epoch",
-		},
-	)
+		nep17tracker: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "nep_17_balance",
+				Help:      "NEP-17 balance of contract and account",
+			},
+			[]string{
+				"symbol", "contract", "account",
+			},
+		),
 
-	storageNodeCapacity = prometheus.NewGaugeVec(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "sn_capacity",
-			Help:      "Storage node capacity (GB)",
-		},
-		[]string{
-			"host", "key",
-		},
-	)
+		nep17trackerTotal: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "nep_17_total_supply",
+				Help:      "NEP-17 total supply of contract",
+			},
+			[]string{
+				"symbol", "contract",
+			},
+		),
 
-	storageNodeTotalCapacity = prometheus.NewGauge(
-		prometheus.GaugeOpts{
-			Namespace: namespace,
-			Name:      "sn_capacity_total",
-			Help:      "Storage nodes total capacity (GB)",
-		},
-	)
-)
+		candidateInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "candidate_info",
+				Help:      "Candidate node info",
+			},
+			[]string{
+				"host", "last_active_epoch",
+			},
+		),
 
-// RegisterFSChainMetrics inits prometheus metrics for side chain. Panics if can't do it.
-func RegisterFSChainMetrics() {
-	prometheus.MustRegister(binaryVersion)
-	prometheus.MustRegister(locationPresent)
-	prometheus.MustRegister(droppedNodesCount)
-	prometheus.MustRegister(newNodesCount)
-	prometheus.MustRegister(epochNumber)
-	prometheus.MustRegister(storageNodeGASBalances)
-	prometheus.MustRegister(storageNodeNotaryBalances)
-	prometheus.MustRegister(innerRingBalances)
-	prometheus.MustRegister(alphabetNotaryBalances)
-	prometheus.MustRegister(proxyBalance)
-	prometheus.MustRegister(fsChainSupply)
-	prometheus.MustRegister(alphabetPubKeys) // used for both monitors
-	prometheus.MustRegister(containersNumber)
-	prometheus.MustRegister(containersSize)
-	prometheus.MustRegister(containersObjects)
-	prometheus.MustRegister(containerSize)
-	prometheus.MustRegister(containerObjects)
-	prometheus.MustRegister(chainHeight)
-	prometheus.MustRegister(chainState)
-	prometheus.MustRegister(nep17tracker)      // used for both monitors
-	prometheus.MustRegister(nep17trackerTotal) // used for both monitors
-	prometheus.MustRegister(candidateInfo)     // used for both monitors
-	prometheus.MustRegister(storageNodeCapacity)
-	prometheus.MustRegister(storageNodeTotalCapacity)
+		storageNodeCapacity: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "sn_capacity",
+				Help:      "Storage node capacity (GB)",
+			},
+			[]string{
+				"host", "key",
+			},
+		),
+
+		storageNodeTotalCapacity: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "sn_capacity_total",
+				Help:      "Storage nodes total capacity (GB)",
+			},
+		),
+	}
+}
+
+// newCollectorMetrics creates run metrics of the named collector.
+func newCollectorMetrics(name string) collectorMetrics {
+	labels := prometheus.Labels{collectorLabel: name}
+
+	return collectorMetrics{
+		duration: prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Namespace:   namespace,
+				Name:        "collector_duration_seconds",
+				Help:        "Duration of collector runs",
+				Buckets:     prometheus.ExponentialBuckets(0.01, 2, 14),
+				ConstLabels: labels,
+			},
+		),
+
+		lastSuccess: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "collector_last_success_timestamp_seconds",
+				Help:        "Unix time of the last successful collector run",
+				ConstLabels: labels,
+			},
+		),
+
+		consecutiveErrors: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "collector_consecutive_errors",
+				Help:        "Number of collector runs failed in a row",
+				ConstLabels: labels,
+			},
+		),
+
+		items: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "collector_items",
+				Help:        "Number of items (nodes, containers, accounts) processed by the last successful collector run",
+				ConstLabels: labels,
+			},
+		),
+	}
+}
+
+// RegisterMetrics inits prometheus metrics of the given collectors in reg.
+// Panics if can't do it.
+func RegisterMetrics(reg prometheus.Registerer, collectors []Collector) {
+	for _, c := range collectors {
+		reg.MustRegister(c.Metrics()...)
+	}
 }
 
-// RegisterMainChainMetrics inits prometheus metrics for main chain. Panics if can't do it.
-func RegisterMainChainMetrics() {
-	prometheus.MustRegister(binaryVersion)
-	prometheus.MustRegister(alphabetGASBalances)
-	prometheus.MustRegister(mainChainSupply)
-	prometheus.MustRegister(alphabetPubKeys)   // used for both monitors
-	prometheus.MustRegister(nep17tracker)      // used for both monitors
-	prometheus.MustRegister(nep17trackerTotal) // used for both monitors
+// RegisterScrapeMetrics inits prometheus metrics of the given collectors run
+// on scrape in reg, see [ScrapeCollector]. Panics if can't do it.
+func RegisterScrapeMetrics(ctx context.Context, reg prometheus.Registerer, collectors []Collector, maxAge time.Duration) {
+	for _, c := range collectors {
+		reg.MustRegister(NewScrapeCollector(ctx, c, maxAge))
+	}
 }
 
-// SetExporterVersion sets neo-exporter version metric.
-func SetExporterVersion(ver string) {
+// registerVersion inits and sets neo-exporter version metric in reg. Panics if
+// can't do it.
+func registerVersion(reg prometheus.Registerer, ver string) {
+	binaryVersion := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Exporter version",
+			Name:      "version",
+			Namespace: namespace,
+		},
+		[]string{"version"},
+	)
+
+	reg.MustRegister(binaryVersion)
 	binaryVersion.WithLabelValues(ver).Add(1)
 }
@@ -4,91 +4,417 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"slices"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/util"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
 type (
 	Nep17BalanceFetcher interface {
-		Fetch(tokenHash util.Uint160, account util.Uint160) (float64, error)
-		FetchTotalSupply(tokenHash util.Uint160) (float64, error)
-		Symbol(tokenHash util.Uint160) (string, error)
+		Fetch(ctx context.Context, tokenHash util.Uint160, account util.Uint160) (float64, error)
+		// FetchMany returns token balances of the given accounts in the same
+		// order.
+		FetchMany(ctx context.Context, tokenHash util.Uint160, accounts []util.Uint160) []BalanceResult
+		FetchTotalSupply(ctx context.Context, tokenHash util.Uint160) (float64, error)
+		Symbol(ctx context.Context, tokenHash util.Uint160) (string, error)
 	}
 
 	NotaryBalanceFetcher interface {
-		FetchNotary(account util.Uint160) (float64, error)
+		FetchNotary(ctx context.Context, account util.Uint160) (float64, error)
+		// FetchNotaryMany returns notary balances of the given accounts in the
+		// same order.
+		FetchNotaryMany(ctx context.Context, accounts []util.Uint160) []BalanceResult
+	}
+
+	// BalanceResult is the result of a single balance request.
+	BalanceResult struct {
+		Value float64
+		Err   error
 	}
 
 	AlphabetFetcher interface {
-		FetchAlphabet() (keys.PublicKeys, error)
+		FetchAlphabet(ctx context.Context) (keys.PublicKeys, error)
+	}
+
+	// Closer releases resources used by the job.
+	Closer interface {
+		// Close releases resources waiting for background routines until ctx
+		// is done.
+		Close(ctx context.Context) error
+	}
+
+	// BlockSubscriber provides notifications about new blocks in chain.
+	BlockSubscriber interface {
+		// Blocks returns a channel of new block heights or nil if block
+		// notifications are not available at the moment. The channel is
+		// closed when notifications stop.
+		Blocks() <-chan uint32
+	}
+
+	// Chain groups parameters of a single chain monitored with others,
+	// collectors of different chains are scheduled independently.
+	Chain struct {
+		// Name is used in logs. Optional.
+		Name string
+		Job  Job
+		// Blocks provides new block notifications. Optional.
+		Blocks BlockSubscriber
+		// Epochs provides NeoFS epoch for collectors running on epoch
+		// change. Optional.
+		Epochs EpochFetcher
+		// Collectors to run instead of all collectors of CollectorJob.
+		// Optional.
+		Collectors []Collector
+		// Schedules overrides default schedule for collectors of
+		// CollectorJob by their names.
+		Schedules map[string]Schedule
+		// Closer is closed by Stop after the job. Optional.
+		Closer Closer
+		// Labels are added to metrics of the chain collectors. Optional.
+		Labels prometheus.Labels
+		// Metrics are updated outside of collectors, e.g. by the chain RPC
+		// client. They're registered with the chain labels too. Optional.
+		Metrics []prometheus.Collector
+	}
+
+	// Args groups parameters to create Monitor. Single chain is defined by
+	// Job, Blocks, Epochs, Collectors and Schedules unless Chains are set.
+	Args struct {
+		Job           Job
+		MetricAddress string
+		// Interval between job runs. It's also used as a fallback when
+		// block notifications are not available.
+		Interval time.Duration
+		// Blocks provides new block notifications. Optional.
+		Blocks BlockSubscriber
+		// EveryBlocks makes the job run after the given number of new
+		// blocks instead of Interval. Zero disables block-driven runs.
+		EveryBlocks uint32
+		// Epochs provides NeoFS epoch for collectors running on epoch
+		// change. Optional.
+		Epochs EpochFetcher
+		// Collectors to run instead of all collectors of CollectorJob.
+		// Optional.
+		Collectors []Collector
+		// Schedules overrides Interval and EveryBlocks for collectors of
+		// CollectorJob by their names.
+		Schedules map[string]Schedule
+		// Chains to monitor, Interval and EveryBlocks are used as their
+		// default schedule. Optional.
+		Chains []Chain
+		// OnScrape disables background job runs, metrics are collected on
+		// scrape, see [ScrapeCollector].
+		OnScrape bool
+		// MaxAge is the time metrics collected on scrape are served for
+		// without running collectors again.
+		MaxAge time.Duration
+		// Registry receives metrics of collectors of all chains and serves
+		// them. A new Registry without labels is used if not set.
+		Registry *Registry
+		// Version is exported by version metric. Optional.
+		Version string
+		// Health serves /healthz and /readyz endpoints. Optional.
+		Health *Health
+		// ShutdownTimeout limits the time Stop waits for the job and metric
+		// requests in progress. Zero means no limit.
+		ShutdownTimeout time.Duration
+		// Closers are closed by Stop after the job. Optional.
+		Closers []Closer
+		Logger  *zap.Logger
 	}
 
 	Monitor struct {
-		job           Job
+		chains        []Chain
 		logger        *zap.Logger
 		sleep         time.Duration
+		everyBlocks   uint32
+		onScrape      bool
+		maxAge        time.Duration
+		registry      *Registry
 		metricsServer http.Server
+
+		shutdownTimeout time.Duration
+		cancel          context.CancelFunc
+		wg              sync.WaitGroup
+
+		// mu protects chains added after the start.
+		mu      sync.Mutex
+		ctx     context.Context
+		stopped bool
+		closers []Closer
 	}
 
 	Job interface {
-		Process()
+		Process(ctx context.Context)
 	}
 )
 
-func New(job Job, metricAddress string, sleep time.Duration, logger *zap.Logger) *Monitor {
-	return &Monitor{
-		job:    job,
-		sleep:  sleep,
-		logger: logger,
+// New creates Monitor and registers metrics of collectors of its chains in
+// args.Registry. Panics if can't do it.
+func New(args Args) *Monitor {
+	registry := args.Registry
+	if registry == nil {
+		registry = NewRegistry(nil)
+	}
+
+	if args.Version != "" {
+		registerVersion(registry, args.Version)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", registry.Handler())
+
+	if args.Health != nil {
+		mux.Handle("/healthz", args.Health.LiveHandler())
+		mux.Handle("/readyz", args.Health.ReadyHandler())
+	}
+
+	chains := args.Chains
+	if len(chains) == 0 && (args.Job != nil || args.Collectors != nil) {
+		chains = []Chain{{
+			Job:        args.Job,
+			Blocks:     args.Blocks,
+			Epochs:     args.Epochs,
+			Collectors: args.Collectors,
+			Schedules:  args.Schedules,
+		}}
+	}
+
+	m := &Monitor{
+		chains:      chains,
+		sleep:       args.Interval,
+		everyBlocks: args.EveryBlocks,
+		onScrape:    args.OnScrape,
+		maxAge:      args.MaxAge,
+		registry:    registry,
+		logger:      args.Logger,
+
+		shutdownTimeout: args.ShutdownTimeout,
+		closers:         args.Closers,
 		metricsServer: http.Server{
-			Addr:    metricAddress,
-			Handler: promhttp.Handler(),
+			Addr:    args.MetricAddress,
+			Handler: mux,
 		},
 	}
+
+	for _, c := range chains {
+		m.registerChain(c)
+	}
+
+	return m
+}
+
+// Registry returns the registry of metrics served by m.
+func (m *Monitor) Registry() *Registry {
+	return m.registry
 }
 
 func (m *Monitor) Start(ctx context.Context) {
-	go func() {
+	ctx, m.cancel = context.WithCancel(ctx)
+
+	m.wg.Go(func() {
 		err := m.metricsServer.ListenAndServe()
 		if !errors.Is(err, http.ErrServerClosed) {
 			m.logger.Error("start metrics server error", zap.Error(err))
 		}
-	}()
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ctx = ctx
+
+	for _, c := range m.chains {
+		m.startChain(c)
+	}
+}
+
+// AddChain registers metrics of the chain collectors and adds the chain to
+// monitor, it's started immediately if the monitor is running. Chains added
+// after Stop are not started, their closers are closed. Panics if metrics
+// can't be registered.
+func (m *Monitor) AddChain(c Chain) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		if c.Closer != nil {
+			if err := c.Closer.Close(context.Background()); err != nil {
+				m.logger.Error("close error", zap.Error(err))
+			}
+		}
+
+		return
+	}
+
+	m.registerChain(c)
+	m.chains = append(m.chains, c)
+
+	if m.ctx != nil {
+		m.startChain(c)
+	}
+}
+
+// registerChain registers metrics of the chain and its collectors labeled
+// with the chain labels. Collectors run on scrape are registered instead of
+// their metrics in scrape mode.
+func (m *Monitor) registerChain(c Chain) {
+	var (
+		reg        = m.registry.With(c.Labels)
+		collectors = chainCollectors(c)
+	)
+
+	reg.MustRegister(c.Metrics...)
+
+	if m.onScrape {
+		// Scrapes made during the shutdown are completed, they're limited by
+		// the metrics server shutdown.
+		RegisterScrapeMetrics(context.Background(), reg, collectors, m.maxAge)
+		return
+	}
 
-	go m.Job(ctx)
+	RegisterMetrics(reg, collectors)
 }
 
+// startChain runs collectors of the chain in background unless they run on
+// scrape. Must be called with m.mu held.
+func (m *Monitor) startChain(c Chain) {
+	if !m.onScrape {
+		m.wg.Go(func() { m.runChain(m.ctx, c) })
+	}
+}
+
+// Stop cancels the job and waits for it and metric requests in progress for
+// the shutdown timeout, then closes closers.
 func (m *Monitor) Stop() {
-	err := m.metricsServer.Close()
-	if err != nil {
+	ctx := context.Background()
+
+	if m.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.shutdownTimeout)
+		defer cancel()
+	}
+
+	m.mu.Lock()
+	m.stopped = true
+	closers := slices.Clone(m.closers)
+
+	for _, c := range m.chains {
+		if c.Closer != nil {
+			closers = append(closers, c.Closer)
+		}
+	}
+	m.mu.Unlock()
+
+	if m.cancel != nil {
+		m.cancel()
+	}
+
+	if err := m.metricsServer.Shutdown(ctx); err != nil {
 		m.logger.Error("stop metrics server error", zap.Error(err))
+
+		_ = m.metricsServer.Close()
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		m.logger.Warn("job is not stopped in time")
+	}
+
+	for _, c := range closers {
+		if err := c.Close(ctx); err != nil {
+			m.logger.Error("close error", zap.Error(err))
+		}
 	}
 }
 
+// Job runs collectors of all chains according to their schedules until ctx is
+// done. Jobs that are not CollectorJob run as a single collector.
 func (m *Monitor) Job(ctx context.Context) {
-	for {
-		m.job.Process()
-
-		select {
-		case <-time.After(m.sleep):
-			// sleep for some time before next prometheus update
-		case <-ctx.Done():
-			m.logger.Info("context closed, stop monitor")
-			return
+	var wg sync.WaitGroup
+
+	m.mu.Lock()
+	chains := slices.Clone(m.chains)
+	m.mu.Unlock()
+
+	for _, c := range chains {
+		wg.Go(func() { m.runChain(ctx, c) })
+	}
+
+	wg.Wait()
+}
+
+// runChain runs collectors of the chain according to their schedules until
+// ctx is done.
+func (m *Monitor) runChain(ctx context.Context, c Chain) {
+	logger := m.logger
+	if c.Name != "" {
+		logger = logger.With(zap.String("chain", c.Name))
+	}
+
+	NewScheduler(logger, c.Blocks, c.Epochs, m.scheduledCollectors(c)).Run(ctx)
+}
+
+// chainCollectors returns collectors of the chain, the job that is not
+// CollectorJob is a single collector without metrics.
+func chainCollectors(chain Chain) []Collector {
+	if chain.Collectors != nil {
+		return chain.Collectors
+	}
+
+	if cj, ok := chain.Job.(CollectorJob); ok {
+		return cj.Collectors()
+	}
+
+	process := func(ctx context.Context) (int, error) {
+		chain.Job.Process(ctx)
+		return 0, nil
+	}
+
+	return []Collector{newCollector("job", process)}
+}
+
+func (m *Monitor) scheduledCollectors(chain Chain) []ScheduledCollector {
+	var (
+		def        = Schedule{Interval: m.sleep, Blocks: m.everyBlocks}
+		collectors = chainCollectors(chain)
+		res        = make([]ScheduledCollector, 0, len(collectors))
+	)
+
+	for _, c := range collectors {
+		s, ok := chain.Schedules[c.Name()]
+		if !ok {
+			s = def
 		}
+
+		res = append(res, ScheduledCollector{Collector: c, Schedule: s})
 	}
+
+	return res
 }
 
 func (m *Monitor) Logger() *zap.Logger {
 	return m.logger
 }
 
+// sortedAlphabet returns hex-encoded keys in ascending order, alphabet itself
+// is not modified since it may be shared with other collectors.
 func sortedAlphabet(alphabet keys.PublicKeys) []string {
+	alphabet = slices.Clone(alphabet)
 	sort.Sort(alphabet)
 	sorted := make([]string, 0, len(alphabet))
 	for _, key := range alphabet {
@@ -98,11 +424,19 @@ func sortedAlphabet(alphabet keys.PublicKeys) []string {
 	return sorted
 }
 
-func processAlphabetPublicKeys(alphabet keys.PublicKeys) {
+func scriptHashes(pubs keys.PublicKeys) []util.Uint160 {
+	res := make([]util.Uint160, 0, len(pubs))
+	for _, key := range pubs {
+		res = append(res, key.GetScriptHash())
+	}
+
+	return res
+}
+
+func processAlphabetPublicKeys(metric *prometheus.GaugeVec, alphabet keys.PublicKeys) {
 	sorted := sortedAlphabet(alphabet)
 
-	alphabetPubKeys.Reset()
 	for _, key := range sorted {
-		alphabetPubKeys.WithLabelValues(key).Set(1)
+		metric.WithLabelValues(key).Set(1)
 	}
 }
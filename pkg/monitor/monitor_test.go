@@ -1,11 +1,17 @@
 package monitor
 
 import (
+	"context"
+	"slices"
+	"sort"
 	"strconv"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestGetDiff(t *testing.T) {
@@ -100,3 +106,159 @@ func generateCandidateNodes(start, finish int) []*CandidateNode {
 
 	return nodes
 }
+
+type testCloser chan struct{}
+
+func (c testCloser) Close(context.Context) error {
+	close(c)
+	return nil
+}
+
+func TestSortedAlphabet(t *testing.T) {
+	var alphabet keys.PublicKeys
+	for range 5 {
+		key, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		alphabet = append(alphabet, key.PublicKey())
+	}
+
+	// Alphabet can be shared with other collectors, it must stay untouched.
+	original := slices.Clone(alphabet)
+	sorted := sortedAlphabet(alphabet)
+
+	require.Equal(t, original, alphabet)
+
+	sort.Sort(original)
+	require.Len(t, sorted, len(original))
+	for i, key := range original {
+		require.Equal(t, key.StringCompressed(), sorted[i])
+	}
+}
+
+func TestMonitorStop(t *testing.T) {
+	newMonitor := func(process func(ctx context.Context) (int, error)) (*Monitor, testCloser) {
+		closer := make(testCloser)
+
+		return New(Args{
+			MetricAddress:   "127.0.0.1:0",
+			Interval:        time.Hour,
+			Collectors:      []Collector{newCollector("test", process)},
+			ShutdownTimeout: 100 * time.Millisecond,
+			Closers:         []Closer{closer},
+			Logger:          zap.NewNop(),
+		}), closer
+	}
+
+	t.Run("job cancelled", func(t *testing.T) {
+		var (
+			started  = make(chan struct{})
+			finished atomic.Bool
+			m, c     = newMonitor(func(ctx context.Context) (int, error) {
+				close(started)
+				<-ctx.Done()
+				finished.Store(true)
+				return 0, ctx.Err()
+			})
+		)
+
+		m.Start(context.Background())
+		<-started
+
+		m.Stop()
+		require.True(t, finished.Load())
+		<-c
+	})
+
+	t.Run("grace period", func(t *testing.T) {
+		var (
+			started = make(chan struct{})
+			release = make(chan struct{})
+			m, c    = newMonitor(func(context.Context) (int, error) {
+				close(started)
+				<-release
+				return 0, nil
+			})
+		)
+
+		t.Cleanup(func() { close(release) })
+
+		m.Start(context.Background())
+		<-started
+
+		m.Stop()
+		// Closers are closed even if the job ignores cancellation.
+		<-c
+	})
+}
+
+func TestMonitorChains(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		runs        = make(chan string, 2)
+		done        = make(chan struct{})
+	)
+
+	// Collectors of different chains have the same name.
+	newChain := func(name string) Chain {
+		return Chain{
+			Name: name,
+			Collectors: []Collector{newCollector("test", func(context.Context) (int, error) {
+				runs <- name
+				return 0, nil
+			})},
+		}
+	}
+
+	m := New(Args{
+		Interval: time.Hour,
+		Chains:   []Chain{newChain("a"), newChain("b")},
+		Logger:   zap.NewNop(),
+	})
+
+	go func() {
+		m.Job(ctx)
+		close(done)
+	}()
+
+	require.ElementsMatch(t, []string{"a", "b"}, []string{<-runs, <-runs})
+
+	cancel()
+	<-done
+}
+
+func TestMonitorAddChain(t *testing.T) {
+	var (
+		runs = make(chan struct{}, 1)
+		m    = New(Args{
+			MetricAddress: "127.0.0.1:0",
+			Interval:      time.Hour,
+			Logger:        zap.NewNop(),
+		})
+		newChain = func(closer Closer) Chain {
+			return Chain{
+				Name: "test",
+				Collectors: []Collector{newCollector("test", func(context.Context) (int, error) {
+					runs <- struct{}{}
+					return 0, nil
+				})},
+				Closer: closer,
+			}
+		}
+	)
+
+	m.Start(context.Background())
+
+	// Chain added to the running monitor is started immediately.
+	running := make(testCloser)
+	m.AddChain(newChain(running))
+	<-runs
+
+	m.Stop()
+	<-running
+
+	// Chain added after the stop is only closed.
+	late := make(testCloser)
+	m.AddChain(newChain(late))
+	<-late
+	require.Empty(t, runs)
+}
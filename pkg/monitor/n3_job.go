@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// N3JobArgs groups parameters to create N3Job.
+	N3JobArgs struct {
+		Logger        *zap.Logger
+		HeightFetcher HeightFetcher
+		StateFetcher  StateFetcher
+		Nep17tracker  *Nep17tracker
+	}
+
+	// N3Job collects metrics of a generic Neo N3 chain without NeoFS
+	// contracts: heights and states of RPC nodes and NEP-17 balances.
+	N3Job struct {
+		logger        *zap.Logger
+		heightFetcher HeightFetcher
+		stateFetcher  StateFetcher
+		nep17tracker  *Nep17tracker
+		metrics       *jobMetrics
+	}
+)
+
+// NewN3Job creates N3Job.
+func NewN3Job(args N3JobArgs) *N3Job {
+	return &N3Job{
+		logger:        args.Logger,
+		heightFetcher: args.HeightFetcher,
+		stateFetcher:  args.StateFetcher,
+		nep17tracker:  args.Nep17tracker,
+		metrics:       newJobMetrics(),
+	}
+}
+
+// Process implements [Job].
+func (m *N3Job) Process(ctx context.Context) {
+	m.logger.Debug("retrieving data from N3 chain")
+
+	processCollectors(ctx, m.Collectors())
+}
+
+// Collectors implements [CollectorJob].
+func (m *N3Job) Collectors() []Collector {
+	collectors := []Collector{
+		newCollector(CollectorChainState, m.processChain, m.metrics.chainHeight, m.metrics.chainState),
+	}
+
+	if m.nep17tracker != nil {
+		collectors = append(collectors, newCollector(CollectorNep17, m.processNep17tracker, m.metrics.nep17tracker,
+			m.metrics.nep17trackerTotal))
+	}
+
+	return collectors
+}
+
+func (m *N3Job) processChain(ctx context.Context) (int, error) {
+	return processChain(ctx, m.metrics, m.heightFetcher, m.stateFetcher)
+}
+
+func (m *N3Job) processNep17tracker(ctx context.Context) (int, error) {
+	return m.nep17tracker.Process(ctx, m.metrics.nep17tracker, m.metrics.nep17trackerTotal)
+}
@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -15,7 +16,7 @@ import (
 type (
 	// NNSResolver helps to resolve NNS contract name to address.
 	NNSResolver interface {
-		ResolveFSContract(name string) (util.Uint160, error)
+		ResolveFSContract(ctx context.Context, name string) (util.Uint160, error)
 	}
 
 	// Item describes task for [Nep17tracker].
@@ -32,7 +33,7 @@ var (
 )
 
 // ParseNep17Tasks prepares tasks for [Nep17tracker].
-func ParseNep17Tasks(balanceFetcher Nep17BalanceFetcher, items []model.Nep17Balance, nns NNSResolver) ([]Item, error) {
+func ParseNep17Tasks(ctx context.Context, balanceFetcher Nep17BalanceFetcher, items []model.Nep17Balance, nns NNSResolver) ([]Item, error) {
 	var (
 		result []Item
 	)
@@ -48,7 +49,7 @@ func ParseNep17Tasks(balanceFetcher Nep17BalanceFetcher, items []model.Nep17Bala
 			Accounts: make([]util.Uint160, 0, len(it.BalanceOf)),
 		}
 
-		contract = nativeNep17ContractHash(it.Contract, nns)
+		contract = nativeNep17ContractHash(ctx, it.Contract, nns)
 		if contract == nil {
 			contract, err = parseUint160(it.Contract)
 			if err != nil {
@@ -57,7 +58,7 @@ func ParseNep17Tasks(balanceFetcher Nep17BalanceFetcher, items []model.Nep17Bala
 			}
 		}
 
-		symbol, err := balanceFetcher.Symbol(*contract)
+		symbol, err := balanceFetcher.Symbol(ctx, *contract)
 		if err != nil {
 			return nil, fmt.Errorf("nep17 contract %s symbol: %w", it.Contract, err)
 		}
@@ -86,14 +87,14 @@ func ParseNep17Tasks(balanceFetcher Nep17BalanceFetcher, items []model.Nep17Bala
 	return result, nil
 }
 
-func nativeNep17ContractHash(name string, nns NNSResolver) *util.Uint160 {
+func nativeNep17ContractHash(ctx context.Context, name string, nns NNSResolver) *util.Uint160 {
 	switch name {
 	case "NEO", "neo":
 		return &neo.Hash
 	case "GAS", "gas":
 		return &gas.Hash
 	default:
-		addr, err := nns.ResolveFSContract(name)
+		addr, err := nns.ResolveFSContract(ctx, name)
 		if err == nil {
 			return &addr
 		}
@@ -1,6 +1,10 @@
 package monitor
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -22,43 +26,69 @@ func NewNep17tracker(balanceFetcher Nep17BalanceFetcher, tasks []Item) (*Nep17tr
 	}, nil
 }
 
-// Process runs the tasks and updates metrics.
-func (n *Nep17tracker) Process(metric *prometheus.GaugeVec, metricTotal *prometheus.GaugeVec) {
-	for _, item := range n.tasks {
-		for _, acc := range item.Accounts {
-			balance, err := n.balanceFetcher.Fetch(item.Hash, acc)
-			if err != nil {
-				zap.L().Error(
-					"nep17 balance",
-					zap.Error(err),
-					zap.String("contract", item.Hash.StringLE()),
-					zap.String("account", address.Uint160ToString(acc)),
-				)
-				continue
-			}
-
-			metric.WithLabelValues(
-				item.Symbol,
-				item.Hash.StringLE(),
-				address.Uint160ToString(acc),
-			).Set(balance)
-		}
+// Process runs the tasks and updates metrics. It returns the number of
+// accounts with updated balances, the error is returned if all requests of
+// some task failed.
+func (n *Nep17tracker) Process(ctx context.Context, metric *prometheus.GaugeVec, metricTotal *prometheus.GaugeVec) (int, error) {
+	var (
+		counts = make([]int, len(n.tasks))
+		errs   = make([]error, len(n.tasks))
+		total  int
+	)
+
+	parallel(len(n.tasks), func(i int) {
+		counts[i], errs[i] = n.processTask(ctx, n.tasks[i], metric, metricTotal)
+	})
+
+	for _, c := range counts {
+		total += c
+	}
 
-		if item.Total {
-			balance, err := n.balanceFetcher.FetchTotalSupply(item.Hash)
-			if err != nil {
-				zap.L().Error(
-					"nep17 total balance",
-					zap.Error(err),
-					zap.String("contract", item.Hash.StringLE()),
-				)
-				continue
-			}
-
-			metricTotal.WithLabelValues(
-				item.Symbol,
-				item.Hash.StringLE(),
-			).Set(balance)
+	return total, errors.Join(errs...)
+}
+
+func (n *Nep17tracker) processTask(ctx context.Context, item Item, metric *prometheus.GaugeVec, metricTotal *prometheus.GaugeVec) (int, error) {
+	balances := n.balanceFetcher.FetchMany(ctx, item.Hash, item.Accounts)
+
+	for i, acc := range item.Accounts {
+		if err := balances[i].Err; err != nil {
+			zap.L().Error(
+				"nep17 balance",
+				zap.Error(err),
+				zap.String("contract", item.Hash.StringLE()),
+				zap.String("account", address.Uint160ToString(acc)),
+			)
+			continue
 		}
+
+		metric.WithLabelValues(
+			item.Symbol,
+			item.Hash.StringLE(),
+			address.Uint160ToString(acc),
+		).Set(balances[i].Value)
 	}
+
+	count, err := balancesResult(item.Symbol, balances)
+
+	if !item.Total {
+		return count, err
+	}
+
+	balance, totalErr := n.balanceFetcher.FetchTotalSupply(ctx, item.Hash)
+	if totalErr != nil {
+		zap.L().Error(
+			"nep17 total balance",
+			zap.Error(totalErr),
+			zap.String("contract", item.Hash.StringLE()),
+		)
+
+		return count, errors.Join(err, fmt.Errorf("%s total supply: %w", item.Symbol, totalErr))
+	}
+
+	metricTotal.WithLabelValues(
+		item.Symbol,
+		item.Hash.StringLE(),
+	).Set(balance)
+
+	return count, err
 }
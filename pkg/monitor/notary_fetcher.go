@@ -1,19 +1,20 @@
 package monitor
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/big"
 
-	"github.com/nspcc-dev/neo-go/pkg/rpcclient/nep17"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 )
 
 type (
 	// NotaryFetcher allows to fetch notary balances from account.
 	NotaryFetcher struct {
-		cli      nep17.Invoker
+		cli      Invoker
 		decimals *big.Float
 	}
 )
@@ -23,7 +24,7 @@ const (
 )
 
 // NewNotaryFetcher is a constructor for NotaryFetcher.
-func NewNotaryFetcher(cli nep17.Invoker) (*NotaryFetcher, error) {
+func NewNotaryFetcher(cli Invoker) (*NotaryFetcher, error) {
 	return &NotaryFetcher{
 		cli:      cli,
 		decimals: big.NewFloat(math.Pow10(decimals)),
@@ -41,8 +42,8 @@ func (b *NotaryFetcher) format(balance *big.Int) (float64, error) {
 }
 
 // FetchNotary returns the notary balance of the given account.
-func (b *NotaryFetcher) FetchNotary(account util.Uint160) (float64, error) {
-	balance, err := notary.NewReader(b.cli).BalanceOf(account)
+func (b *NotaryFetcher) FetchNotary(ctx context.Context, account util.Uint160) (float64, error) {
+	balance, err := unwrap.BigInt(b.cli.Call(ctx, notary.Hash, "balanceOf", account))
 	if err != nil {
 		return 0, fmt.Errorf("balanceOf: %w", err)
 	}
@@ -54,3 +55,29 @@ func (b *NotaryFetcher) FetchNotary(account util.Uint160) (float64, error) {
 
 	return res, nil
 }
+
+// FetchNotaryMany returns notary balances of the given accounts in the same
+// order. Requests are combined into a few RPC invocations.
+func (b *NotaryFetcher) FetchNotaryMany(ctx context.Context, accounts []util.Uint160) []BalanceResult {
+	calls := make([]contractCall, 0, len(accounts))
+	for _, acc := range accounts {
+		calls = append(calls, contractCall{contract: notary.Hash, method: "balanceOf", params: []any{acc}})
+	}
+
+	results := make([]BalanceResult, 0, len(accounts))
+	for _, r := range invokeBatch(ctx, b.cli, calls) {
+		var res BalanceResult
+
+		if r.err != nil {
+			res.Err = fmt.Errorf("balanceOf: %w", r.err)
+		} else if balance, err := r.item.TryInteger(); err != nil {
+			res.Err = fmt.Errorf("balanceOf: %w", err)
+		} else {
+			res.Value, res.Err = b.format(balance)
+		}
+
+		results = append(results, res)
+	}
+
+	return results
+}
@@ -0,0 +1,60 @@
+package monitor
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Registry is the private set of metrics served by the [Monitor]. Unlike the
+// default prometheus registry it can be created several times in a process,
+// so several monitors (e.g. embedded into other applications or run by
+// tests) don't interfere.
+type Registry struct {
+	registry   *prometheus.Registry
+	registerer prometheus.Registerer
+}
+
+// NewRegistry creates Registry adding labels to all metrics registered in it.
+// Labels are optional.
+func NewRegistry(labels prometheus.Labels) *Registry {
+	reg := prometheus.NewRegistry()
+
+	return &Registry{
+		registry:   reg,
+		registerer: prometheus.WrapRegistererWith(labels, reg),
+	}
+}
+
+// Register implements [prometheus.Registerer].
+func (r *Registry) Register(c prometheus.Collector) error {
+	return r.registerer.Register(c)
+}
+
+// MustRegister implements [prometheus.Registerer].
+func (r *Registry) MustRegister(cs ...prometheus.Collector) {
+	r.registerer.MustRegister(cs...)
+}
+
+// Unregister implements [prometheus.Registerer].
+func (r *Registry) Unregister(c prometheus.Collector) bool {
+	return r.registerer.Unregister(c)
+}
+
+// Gather implements [prometheus.Gatherer].
+func (r *Registry) Gather() ([]*dto.MetricFamily, error) {
+	return r.registry.Gather()
+}
+
+// With returns the registerer adding labels to metrics registered in r along
+// with labels of r.
+func (r *Registry) With(labels prometheus.Labels) prometheus.Registerer {
+	return prometheus.WrapRegistererWith(labels, r.registerer)
+}
+
+// Handler returns the HTTP handler serving metrics of r.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
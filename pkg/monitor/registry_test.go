@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	// Monitors with the same metrics don't interfere.
+	for _, instance := range []string{"a", "b"} {
+		t.Run(instance, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				gauge    = prometheus.NewGauge(prometheus.GaugeOpts{Namespace: namespace, Name: "test", Help: "Test gauge"})
+				rpcGauge = prometheus.NewGauge(prometheus.GaugeOpts{Namespace: namespace, Name: "test_rpc", Help: "Test RPC gauge"})
+				m        = New(Args{
+					Registry: NewRegistry(prometheus.Labels{"instance": instance}),
+					Version:  "v1.0.0",
+					Chains: []Chain{{
+						Name: "test",
+						Collectors: []Collector{newCollector("test", func(context.Context) (int, error) {
+							gauge.Set(1)
+							return 1, nil
+						}, gauge)},
+						Labels:  prometheus.Labels{"chain": "test"},
+						Metrics: []prometheus.Collector{rpcGauge},
+					}},
+					Logger: zap.NewNop(),
+				})
+			)
+
+			_, err := m.chains[0].Collectors[0].Process(context.Background())
+			require.NoError(t, err)
+
+			rpcGauge.Set(2)
+
+			expected := `
+# HELP neo_exporter_test Test gauge
+# TYPE neo_exporter_test gauge
+neo_exporter_test{chain="test",instance="` + instance + `"} 1
+# HELP neo_exporter_test_rpc Test RPC gauge
+# TYPE neo_exporter_test_rpc gauge
+neo_exporter_test_rpc{chain="test",instance="` + instance + `"} 2
+# HELP neo_exporter_version Exporter version
+# TYPE neo_exporter_version gauge
+neo_exporter_version{instance="` + instance + `",version="v1.0.0"} 1
+`
+
+			require.NoError(t, testutil.GatherAndCompare(m.Registry(), strings.NewReader(expected)))
+
+			w := httptest.NewRecorder()
+			m.Registry().Handler().ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+			body, err := io.ReadAll(w.Result().Body)
+			require.NoError(t, err)
+			require.Contains(t, string(body), `neo_exporter_test{chain="test",instance="`+instance+`"} 1`)
+		})
+	}
+}
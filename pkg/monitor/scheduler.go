@@ -0,0 +1,257 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// EpochFetcher provides the current NeoFS epoch.
+	EpochFetcher interface {
+		Epoch(ctx context.Context) (int64, error)
+	}
+
+	// Schedule defines when the collector runs.
+	Schedule struct {
+		// Interval between collector runs. It's also used as a fallback
+		// when block notifications or epoch are not available. Zero
+		// disables interval runs, it's allowed for collectors driven by
+		// blocks or epoch only, they don't run without block
+		// notifications then.
+		Interval time.Duration
+		// Blocks makes the collector run after the given number of new
+		// blocks instead of Interval. Zero disables block-driven runs.
+		Blocks uint32
+		// Epoch makes the collector run on NeoFS epoch change instead of
+		// Interval. The epoch is checked on every new block or every
+		// Interval if block notifications are not available. The epoch
+		// is read in the collector goroutine before the run.
+		Epoch bool
+	}
+
+	// ScheduledCollector is the collector with its schedule.
+	ScheduledCollector struct {
+		Collector
+		Schedule Schedule
+	}
+
+	// Scheduler runs collectors according to their schedules, every
+	// collector runs in its own goroutine. Runs of collectors still running
+	// when the next run is due are skipped.
+	Scheduler struct {
+		logger     *zap.Logger
+		blocks     BlockSubscriber
+		epochs     EpochFetcher
+		collectors []*scheduled
+	}
+
+	scheduled struct {
+		ScheduledCollector
+
+		running atomic.Bool
+		started bool
+		// next is the time of the next interval check.
+		next      time.Time
+		lastBlock uint32
+		// lastEpoch and epochRead are accessed by the collector
+		// goroutine only.
+		lastEpoch int64
+		epochRead bool
+	}
+)
+
+// NewScheduler creates Scheduler. Blocks and epochs are optional, schedules
+// depending on them fall back to intervals without them.
+func NewScheduler(logger *zap.Logger, blocks BlockSubscriber, epochs EpochFetcher, collectors []ScheduledCollector) *Scheduler {
+	s := &Scheduler{
+		logger: logger,
+		blocks: blocks,
+		epochs: epochs,
+	}
+
+	for _, c := range collectors {
+		s.collectors = append(s.collectors, &scheduled{ScheduledCollector: c})
+	}
+
+	return s
+}
+
+// Run runs collectors until ctx is done and waits for the running ones.
+func (s *Scheduler) Run(ctx context.Context) {
+	var (
+		wg     sync.WaitGroup
+		blocks <-chan uint32
+		// height is the new block height, zero if woken up by the timer.
+		height uint32
+	)
+
+	defer wg.Wait()
+
+	for {
+		if blocks == nil && s.blocks != nil && s.needBlocks() {
+			blocks = s.blocks.Blocks()
+		}
+
+		s.tick(ctx, &wg, time.Now(), height, blocks != nil)
+
+		var (
+			timer  *time.Timer
+			timerC <-chan time.Time
+		)
+
+		if d, ok := s.nextTick(time.Now(), blocks != nil); ok {
+			timer = time.NewTimer(d)
+			timerC = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+
+			s.logger.Info("context closed, stop monitor")
+
+			return
+		case <-timerC:
+			height = 0
+		case h, ok := <-blocks:
+			if timer != nil {
+				timer.Stop()
+			}
+
+			height = h
+
+			if !ok {
+				s.logger.Warn("new block notifications stopped, fall back to polling")
+
+				blocks = nil
+				height = 0
+			}
+		}
+	}
+}
+
+// needBlocks checks whether some collector depends on new blocks.
+func (s *Scheduler) needBlocks() bool {
+	for _, c := range s.collectors {
+		if c.Schedule.Blocks > 0 || c.Schedule.Epoch {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tick starts collectors due at the moment. Height is the new block height
+// or zero if there is no new block. Epoch-driven collectors read the epoch in
+// their goroutines, so slow RPC nodes don't delay the scheduling loop.
+func (s *Scheduler) tick(ctx context.Context, wg *sync.WaitGroup, now time.Time, height uint32, blocksOn bool) {
+	for _, c := range s.collectors {
+		if c.running.Load() {
+			if !now.Before(c.next) {
+				s.logger.Debug("collector is still running, skip the run", zap.String("collector", c.Name()))
+				c.next = now.Add(c.Schedule.Interval)
+			}
+
+			continue
+		}
+
+		if !s.due(c, now, height, blocksOn) {
+			continue
+		}
+
+		var (
+			first       = !c.started
+			checkEpoch  = c.Schedule.Epoch && s.epochs != nil && (c.Schedule.Blocks == 0 || !blocksOn)
+			intervalDue = c.intervalPassed(now)
+		)
+
+		c.started = true
+		c.next = now.Add(c.Schedule.Interval)
+
+		if height != 0 {
+			c.lastBlock = height
+		}
+
+		c.running.Store(true)
+
+		wg.Go(func() {
+			defer c.running.Store(false)
+
+			if checkEpoch && !s.epochChanged(ctx, c, first || intervalDue) {
+				return
+			}
+
+			// Errors are logged by collectors.
+			_, _ = c.Process(ctx)
+		})
+	}
+}
+
+// due checks whether the collector must run or check the epoch.
+func (s *Scheduler) due(c *scheduled, now time.Time, height uint32, blocksOn bool) bool {
+	if !c.started {
+		return true
+	}
+
+	if c.Schedule.Blocks > 0 && blocksOn {
+		return height != 0 && (c.lastBlock == 0 || height >= c.lastBlock+c.Schedule.Blocks)
+	}
+
+	if c.Schedule.Epoch && s.epochs != nil && height != 0 {
+		return true
+	}
+
+	return c.intervalPassed(now)
+}
+
+// intervalPassed checks whether the collector is due by its interval.
+func (c *scheduled) intervalPassed(now time.Time) bool {
+	return c.Schedule.Interval > 0 && !now.Before(c.next)
+}
+
+// epochChanged reads the current epoch and checks whether it differs from the
+// one of the last collector run. If the epoch can't be read, fallback is
+// returned.
+func (s *Scheduler) epochChanged(ctx context.Context, c *scheduled, fallback bool) bool {
+	e, err := s.epochs.Epoch(ctx)
+	if err != nil {
+		s.logger.Warn("can't read NeoFS epoch", zap.String("collector", c.Name()), zap.Error(err))
+		return fallback
+	}
+
+	if c.epochRead && e == c.lastEpoch {
+		return false
+	}
+
+	c.lastEpoch, c.epochRead = e, true
+
+	return true
+}
+
+// nextTick returns the time until the next interval check. False is returned
+// if all collectors are driven by new blocks or have no interval.
+func (s *Scheduler) nextTick(now time.Time, blocksOn bool) (time.Duration, bool) {
+	var (
+		next time.Time
+		ok   bool
+	)
+
+	for _, c := range s.collectors {
+		if c.Schedule.Interval <= 0 || blocksOn && (c.Schedule.Blocks > 0 || c.Schedule.Epoch && s.epochs != nil) {
+			continue
+		}
+
+		if !ok || c.next.Before(next) {
+			next = c.next
+			ok = true
+		}
+	}
+
+	return max(next.Sub(now), 0), ok
+}
@@ -0,0 +1,244 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testBlocks struct {
+	ch   chan uint32
+	once bool
+}
+
+func (b *testBlocks) Blocks() <-chan uint32 {
+	if b.ch == nil {
+		return nil
+	}
+
+	ch := b.ch
+	if b.once {
+		b.ch = nil
+	}
+
+	return ch
+}
+
+type testEpochs struct {
+	epoch atomic.Int64
+	err   error
+}
+
+func (e *testEpochs) Epoch(context.Context) (int64, error) {
+	return e.epoch.Load(), e.err
+}
+
+// slowEpochs blocks epoch requests until release is closed.
+type slowEpochs struct {
+	release chan struct{}
+}
+
+func (e *slowEpochs) Epoch(ctx context.Context) (int64, error) {
+	select {
+	case <-e.release:
+		return 1, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// testCollector reports every run to the channel.
+type testCollector chan struct{}
+
+func (c testCollector) Name() string {
+	return "test"
+}
+
+func (c testCollector) Process(context.Context) (int, error) {
+	c <- struct{}{}
+	return 0, nil
+}
+
+func (c testCollector) Metrics() []prometheus.Collector {
+	return nil
+}
+
+func startScheduler(t *testing.T, blocks BlockSubscriber, epochs EpochFetcher, s Schedule) (*Scheduler, testCollector) {
+	var (
+		c         = make(testCollector, 100)
+		sch       = NewScheduler(zap.NewNop(), blocks, epochs, []ScheduledCollector{{Collector: c, Schedule: s}})
+		ctx, stop = context.WithCancel(context.Background())
+		done      = make(chan struct{})
+	)
+
+	go func() {
+		sch.Run(ctx)
+		close(done)
+	}()
+
+	t.Cleanup(func() {
+		stop()
+		<-done
+	})
+
+	return sch, c
+}
+
+func requireRun(t *testing.T, s *Scheduler, c testCollector) {
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Fatal("collector hasn't run")
+	}
+
+	require.Eventually(t, func() bool { return !s.collectors[0].running.Load() }, time.Second, time.Millisecond)
+}
+
+func requireNoRun(t *testing.T, c testCollector) {
+	require.Never(t, func() bool { return len(c) > 0 }, 50*time.Millisecond, 5*time.Millisecond)
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("interval", func(t *testing.T) {
+		s, c := startScheduler(t, nil, nil, Schedule{Interval: time.Millisecond})
+
+		for range 3 {
+			requireRun(t, s, c)
+		}
+	})
+
+	t.Run("every N blocks", func(t *testing.T) {
+		blocks := &testBlocks{ch: make(chan uint32, 10)}
+		s, c := startScheduler(t, blocks, nil, Schedule{Interval: time.Hour, Blocks: 3})
+
+		requireRun(t, s, c)
+
+		blocks.ch <- 10
+		requireRun(t, s, c)
+
+		blocks.ch <- 11
+		blocks.ch <- 12
+		requireNoRun(t, c)
+
+		blocks.ch <- 13
+		requireRun(t, s, c)
+		require.Empty(t, blocks.ch)
+	})
+
+	t.Run("fallback to interval", func(t *testing.T) {
+		blocks := &testBlocks{ch: make(chan uint32), once: true}
+		close(blocks.ch)
+
+		s, c := startScheduler(t, blocks, nil, Schedule{Interval: time.Millisecond, Blocks: 1})
+
+		for range 3 {
+			requireRun(t, s, c)
+		}
+	})
+
+	t.Run("epoch", func(t *testing.T) {
+		epochs := new(testEpochs)
+		s, c := startScheduler(t, nil, epochs, Schedule{Interval: time.Millisecond, Epoch: true})
+
+		requireRun(t, s, c)
+		requireNoRun(t, c)
+
+		epochs.epoch.Store(1)
+		requireRun(t, s, c)
+		requireNoRun(t, c)
+	})
+
+	t.Run("epoch on blocks", func(t *testing.T) {
+		var (
+			blocks = &testBlocks{ch: make(chan uint32, 10)}
+			epochs = new(testEpochs)
+		)
+
+		s, c := startScheduler(t, blocks, epochs, Schedule{Interval: time.Hour, Epoch: true})
+
+		requireRun(t, s, c)
+
+		blocks.ch <- 10
+		requireNoRun(t, c)
+
+		epochs.epoch.Store(1)
+		blocks.ch <- 11
+		requireRun(t, s, c)
+	})
+
+	t.Run("epoch unavailable", func(t *testing.T) {
+		epochs := &testEpochs{err: errors.New("netmap contract is not available")}
+		s, c := startScheduler(t, nil, epochs, Schedule{Interval: time.Millisecond, Epoch: true})
+
+		for range 3 {
+			requireRun(t, s, c)
+		}
+	})
+
+	t.Run("no interval", func(t *testing.T) {
+		s, c := startScheduler(t, nil, nil, Schedule{Blocks: 1})
+
+		// Collectors without interval run once without block notifications.
+		requireRun(t, s, c)
+		requireNoRun(t, c)
+
+		_, ok := s.nextTick(time.Now(), false)
+		require.False(t, ok)
+	})
+
+	t.Run("slow epoch", func(t *testing.T) {
+		var (
+			epochs   = &slowEpochs{release: make(chan struct{})}
+			interval = make(testCollector, 100)
+			epoch    = make(testCollector, 100)
+			sch      = NewScheduler(zap.NewNop(), nil, epochs, []ScheduledCollector{
+				{Collector: epoch, Schedule: Schedule{Interval: time.Millisecond, Epoch: true}},
+				{Collector: interval, Schedule: Schedule{Interval: time.Millisecond}},
+			})
+			ctx, stop = context.WithCancel(context.Background())
+			done      = make(chan struct{})
+		)
+
+		go func() {
+			sch.Run(ctx)
+			close(done)
+		}()
+
+		// Epoch is read in the collector goroutine, others are not delayed.
+		for range 3 {
+			select {
+			case <-interval:
+			case <-time.After(time.Second):
+				t.Fatal("collector hasn't run")
+			}
+		}
+
+		require.Empty(t, epoch)
+
+		close(epochs.release)
+
+		select {
+		case <-epoch:
+		case <-time.After(time.Second):
+			t.Fatal("collector hasn't run")
+		}
+
+		stop()
+		<-done
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		c := make(testCollector, 1)
+		NewScheduler(zap.NewNop(), nil, nil, []ScheduledCollector{{Collector: c, Schedule: Schedule{Interval: time.Hour}}}).Run(ctx)
+		require.Len(t, c, 1)
+	})
+}
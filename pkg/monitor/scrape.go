@@ -0,0 +1,111 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+type (
+	// ScrapeCollector is the [prometheus.Collector] running the [Collector]
+	// on scrape. Values are cached for the configured max age, every scrape
+	// gets the snapshot of all collector metrics made right after its run,
+	// so partially updated metrics are never exposed.
+	ScrapeCollector struct {
+		ctx       context.Context
+		collector Collector
+		maxAge    time.Duration
+
+		mu       sync.Mutex
+		updated  time.Time
+		snapshot []prometheus.Metric
+	}
+
+	// frozenMetric is the metric value written at some moment.
+	frozenMetric struct {
+		desc *prometheus.Desc
+		pb   *dto.Metric
+	}
+)
+
+// NewScrapeCollector creates ScrapeCollector running c with ctx at most once
+// per maxAge.
+func NewScrapeCollector(ctx context.Context, c Collector, maxAge time.Duration) *ScrapeCollector {
+	return &ScrapeCollector{
+		ctx:       ctx,
+		collector: c,
+		maxAge:    maxAge,
+	}
+}
+
+// Describe implements [prometheus.Collector]. Metrics of the collector may be
+// known only after the run (e.g. with [StalePolicy]), so the collector is
+// unchecked.
+func (s *ScrapeCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements [prometheus.Collector]. Concurrent scrapes wait for the
+// single collector run.
+func (s *ScrapeCollector) Collect(ch chan<- prometheus.Metric) {
+	s.mu.Lock()
+
+	if s.snapshot == nil || time.Since(s.updated) >= s.maxAge {
+		// Errors are logged by the collector, metrics it failed to update
+		// are exposed as is.
+		_, _ = s.collector.Process(s.ctx)
+		s.snapshot = freeze(s.collector.Metrics())
+		s.updated = time.Now()
+	}
+
+	snapshot := s.snapshot
+
+	s.mu.Unlock()
+
+	for _, m := range snapshot {
+		ch <- m
+	}
+}
+
+// freeze returns current values of metrics.
+func freeze(cs []prometheus.Collector) []prometheus.Metric {
+	var (
+		ch  = make(chan prometheus.Metric)
+		res = make([]prometheus.Metric, 0, len(cs))
+	)
+
+	go func() {
+		for _, c := range cs {
+			c.Collect(ch)
+		}
+
+		close(ch)
+	}()
+
+	for m := range ch {
+		pb := new(dto.Metric)
+		if err := m.Write(pb); err != nil {
+			res = append(res, prometheus.NewInvalidMetric(m.Desc(), err))
+			continue
+		}
+
+		res = append(res, &frozenMetric{desc: m.Desc(), pb: pb})
+	}
+
+	return res
+}
+
+// Desc implements [prometheus.Metric].
+func (f *frozenMetric) Desc() *prometheus.Desc {
+	return f.desc
+}
+
+// Write implements [prometheus.Metric].
+func (f *frozenMetric) Write(out *dto.Metric) error {
+	proto.Reset(out)
+	proto.Merge(out, f.pb)
+
+	return nil
+}
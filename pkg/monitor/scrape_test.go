@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrapeCollector(t *testing.T) {
+	newScrape := func(maxAge time.Duration) (*prometheus.Registry, *prometheus.GaugeVec, *int) {
+		var (
+			runs  = new(int)
+			gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test", Help: "Test"}, []string{"key"})
+			c     = newCollector("test", func(context.Context) (int, error) {
+				*runs++
+				gauge.Reset()
+				gauge.WithLabelValues("a").Set(float64(*runs))
+				return 1, nil
+			}, gauge)
+			reg = prometheus.NewPedanticRegistry()
+		)
+
+		reg.MustRegister(NewScrapeCollector(context.Background(), c, maxAge))
+
+		return reg, gauge, runs
+	}
+
+	requireValue := func(t *testing.T, reg *prometheus.Registry, v string) {
+		require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP test Test
+# TYPE test gauge
+test{key="a"} `+v+`
+`)))
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		reg, gauge, runs := newScrape(time.Hour)
+
+		requireValue(t, reg, "1")
+
+		// Snapshot isn't affected by further changes.
+		gauge.Reset()
+		gauge.WithLabelValues("b").Set(10)
+
+		requireValue(t, reg, "1")
+		require.Equal(t, 1, *runs)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		reg, _, runs := newScrape(0)
+
+		requireValue(t, reg, "1")
+		requireValue(t, reg, "2")
+		require.Equal(t, 2, *runs)
+	})
+}
@@ -0,0 +1,271 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// StaleMode defines what happens with series not updated by the collector
+// run.
+type StaleMode string
+
+// Stale series modes.
+const (
+	// StaleDrop removes series as soon as a run doesn't update them.
+	StaleDrop StaleMode = "drop"
+	// StaleKeep keeps last values of series for the configured number of
+	// runs.
+	StaleKeep StaleMode = "keep"
+	// StaleMark keeps last values of series until they're updated again.
+	StaleMark StaleMode = "mark"
+)
+
+type (
+	// StalePolicy defines how series not updated by the collector run (e.g.
+	// because of a failed request or removed node) are exported.
+	StalePolicy struct {
+		Mode StaleMode
+		// Cycles is the number of runs series are kept for in StaleKeep
+		// mode.
+		Cycles int
+		// Age enables the `<name>_age_seconds` gauge with the time since the
+		// last update of every series. It doubles the number of series. It's
+		// always enabled in StaleMark mode, since the age is the only way to
+		// tell stale series from the fresh ones there.
+		Age bool
+	}
+
+	// staleCollector is the [Collector] exporting its metrics according to
+	// the [StalePolicy]. Metrics of the wrapped collector are used as a
+	// staging area for values of a single run.
+	staleCollector struct {
+		Collector
+
+		policy  StalePolicy
+		staging *prometheus.Registry
+
+		mu       sync.Mutex
+		run      uint64
+		families map[string]*staleFamily
+		series   map[string]*staleSeries
+		// stale is the number of series with values from previous runs by
+		// metric names.
+		stale map[string]int
+	}
+
+	staleFamily struct {
+		name      string
+		desc      *prometheus.Desc
+		ageDesc   *prometheus.Desc
+		valueType prometheus.ValueType
+	}
+
+	staleSeries struct {
+		family  *staleFamily
+		labels  []string
+		value   float64
+		updated time.Time
+		run     uint64
+		missed  int
+	}
+)
+
+// WithStalePolicy returns the collector exporting values of its metrics
+// according to the policy. The number of series with values from previous
+// runs is exported as `stale_series`. Only gauge, counter and untyped metrics
+// are supported.
+func WithStalePolicy(c Collector, policy StalePolicy) Collector {
+	if policy.Mode == StaleMark {
+		policy.Age = true
+	}
+
+	staging := prometheus.NewRegistry()
+	staging.MustRegister(c.Metrics()...)
+
+	return &staleCollector{
+		Collector: c,
+		policy:    policy,
+		staging:   staging,
+		families:  make(map[string]*staleFamily),
+		series:    make(map[string]*staleSeries),
+	}
+}
+
+// Process implements [Collector]. Metrics of the wrapped collector are unset
+// before the run, so that only values set by the run are exported as fresh.
+func (c *staleCollector) Process(ctx context.Context) (int, error) {
+	for _, m := range c.Collector.Metrics() {
+		switch m := m.(type) {
+		case interface{ Reset() }:
+			m.Reset()
+		case prometheus.Gauge:
+			// Plain gauges can't be removed, NaN marks them unset.
+			m.Set(math.NaN())
+		}
+	}
+
+	n, err := c.Collector.Process(ctx)
+
+	// Consistent metrics are gathered even on error.
+	families, gatherErr := c.staging.Gather()
+	if gatherErr != nil {
+		err = errors.Join(err, fmt.Errorf("gather metrics: %w", gatherErr))
+	}
+
+	c.update(families, time.Now())
+
+	return n, err
+}
+
+// Metrics implements [Collector].
+func (c *staleCollector) Metrics() []prometheus.Collector {
+	return []prometheus.Collector{c}
+}
+
+// Describe implements [prometheus.Collector]. Metrics are known only after
+// the run, so the collector is unchecked.
+func (c *staleCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements [prometheus.Collector].
+func (c *staleCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+
+	var (
+		now = time.Now()
+		res = make([]prometheus.Metric, 0, 2*len(c.series)+len(c.stale))
+	)
+
+	for _, s := range c.series {
+		res = append(res, prometheus.MustNewConstMetric(s.family.desc, s.family.valueType, s.value, s.labels...))
+
+		if c.policy.Age {
+			res = append(res, prometheus.MustNewConstMetric(s.family.ageDesc, prometheus.GaugeValue, now.Sub(s.updated).Seconds(), s.labels...))
+		}
+	}
+
+	for name, n := range c.stale {
+		res = append(res, prometheus.MustNewConstMetric(staleSeriesDesc, prometheus.GaugeValue, float64(n), c.Name(), name))
+	}
+
+	c.mu.Unlock()
+
+	for _, m := range res {
+		ch <- m
+	}
+}
+
+// update merges values gathered after the run and applies the policy to
+// series the run hasn't updated.
+func (c *staleCollector) update(families []*dto.MetricFamily, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.run++
+
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			v, ok := metricValue(f.GetType(), m)
+			if !ok || math.IsNaN(v) {
+				continue
+			}
+
+			fam := c.family(f)
+			labels := make([]string, 0, len(m.GetLabel()))
+
+			for _, l := range m.GetLabel() {
+				labels = append(labels, l.GetValue())
+			}
+
+			key := seriesKey(f.GetName(), labels)
+
+			s, ok := c.series[key]
+			if !ok {
+				s = &staleSeries{family: fam, labels: labels}
+				c.series[key] = s
+			}
+
+			s.value = v
+			s.updated = now
+			s.run = c.run
+			s.missed = 0
+		}
+	}
+
+	c.stale = make(map[string]int)
+
+	for key, s := range c.series {
+		if s.run == c.run {
+			continue
+		}
+
+		s.missed++
+
+		if c.policy.Mode == StaleDrop || c.policy.Mode == StaleKeep && s.missed > c.policy.Cycles {
+			delete(c.series, key)
+			continue
+		}
+
+		c.stale[s.family.name]++
+	}
+}
+
+// family returns the family of the gathered metric creating it if needed.
+// Must be called with c.mu held.
+func (c *staleCollector) family(f *dto.MetricFamily) *staleFamily {
+	if fam, ok := c.families[f.GetName()]; ok {
+		return fam
+	}
+
+	var labelNames []string
+
+	if ms := f.GetMetric(); len(ms) != 0 {
+		for _, l := range ms[0].GetLabel() {
+			labelNames = append(labelNames, l.GetName())
+		}
+	}
+
+	valueType := prometheus.GaugeValue
+
+	switch f.GetType() {
+	case dto.MetricType_COUNTER:
+		valueType = prometheus.CounterValue
+	case dto.MetricType_UNTYPED:
+		valueType = prometheus.UntypedValue
+	}
+
+	fam := &staleFamily{
+		name:      f.GetName(),
+		desc:      prometheus.NewDesc(f.GetName(), f.GetHelp(), labelNames, nil),
+		ageDesc:   prometheus.NewDesc(f.GetName()+"_age_seconds", "Seconds since "+f.GetName()+" value was updated", labelNames, nil),
+		valueType: valueType,
+	}
+
+	c.families[f.GetName()] = fam
+
+	return fam
+}
+
+func metricValue(t dto.MetricType, m *dto.Metric) (float64, bool) {
+	switch t {
+	case dto.MetricType_GAUGE:
+		return m.GetGauge().GetValue(), true
+	case dto.MetricType_COUNTER:
+		return m.GetCounter().GetValue(), true
+	case dto.MetricType_UNTYPED:
+		return m.GetUntyped().GetValue(), true
+	default:
+		return 0, false
+	}
+}
+
+func seriesKey(name string, labels []string) string {
+	return name + "\x00" + strings.Join(labels, "\x00")
+}
@@ -0,0 +1,157 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStalePolicy(t *testing.T) {
+	errTest := errors.New("test")
+
+	// newStale creates the collector setting the given keys of the vector
+	// and the plain gauge on success.
+	newStale := func(t *testing.T, policy StalePolicy) (*prometheus.Registry, func(err error, keys ...string)) {
+		var (
+			name  = "stale_test_" + string(policy.Mode)
+			vec   = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_vec", Help: "Test"}, []string{"key"})
+			gauge = prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge", Help: "Test"})
+			keys  []string
+			err   error
+			c     = WithStalePolicy(newCollector(name, func(context.Context) (int, error) {
+				for _, k := range keys {
+					vec.WithLabelValues(k).Set(1)
+				}
+
+				if err == nil {
+					gauge.Set(1)
+				}
+
+				return len(keys), err
+			}, vec, gauge), policy)
+			reg = prometheus.NewPedanticRegistry()
+		)
+
+		reg.MustRegister(c.Metrics()...)
+
+		return reg, func(e error, ks ...string) {
+			keys, err = ks, e
+
+			_, runErr := c.Process(context.Background())
+			require.ErrorIs(t, runErr, e)
+		}
+	}
+
+	// requireSeries checks exported series of test_vec and test_gauge and
+	// presence of their age gauges if age is set.
+	requireSeries := func(t *testing.T, reg *prometheus.Registry, age bool, keys []string, gauge bool) {
+		families, err := reg.Gather()
+		require.NoError(t, err)
+
+		got := make(map[string][]*dto.Metric)
+		for _, f := range families {
+			got[f.GetName()] = f.GetMetric()
+		}
+
+		var vecKeys []string
+		for _, m := range got["test_vec"] {
+			vecKeys = append(vecKeys, m.GetLabel()[0].GetValue())
+		}
+
+		require.ElementsMatch(t, keys, vecKeys)
+
+		if !age {
+			require.NotContains(t, got, "test_vec_age_seconds")
+			require.NotContains(t, got, "test_gauge_age_seconds")
+		} else {
+			require.Len(t, got["test_vec_age_seconds"], len(keys))
+		}
+
+		if gauge {
+			require.Len(t, got["test_gauge"], 1)
+
+			if age {
+				require.Len(t, got["test_gauge_age_seconds"], 1)
+			}
+		} else {
+			require.NotContains(t, got, "test_gauge")
+		}
+	}
+
+	// staleNumber returns the exported number of stale series of the metric.
+	staleNumber := func(t *testing.T, reg *prometheus.Registry, metric string) float64 {
+		families, err := reg.Gather()
+		require.NoError(t, err)
+
+		for _, f := range families {
+			if f.GetName() != "neo_exporter_stale_series" {
+				continue
+			}
+
+			for _, m := range f.GetMetric() {
+				for _, l := range m.GetLabel() {
+					if l.GetName() == "metric" && l.GetValue() == metric {
+						return m.GetGauge().GetValue()
+					}
+				}
+			}
+		}
+
+		return 0
+	}
+
+	t.Run("drop", func(t *testing.T) {
+		reg, run := newStale(t, StalePolicy{Mode: StaleDrop, Age: true})
+
+		run(nil, "a", "b")
+		requireSeries(t, reg, true, []string{"a", "b"}, true)
+
+		run(nil, "a")
+		requireSeries(t, reg, true, []string{"a"}, true)
+
+		run(errTest)
+		requireSeries(t, reg, true, nil, false)
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		reg, run := newStale(t, StalePolicy{Mode: StaleKeep, Cycles: 2})
+
+		run(nil, "a", "b")
+
+		run(errTest, "a")
+		requireSeries(t, reg, false, []string{"a", "b"}, true)
+		require.EqualValues(t, 1, staleNumber(t, reg, "test_vec"))
+		require.EqualValues(t, 1, staleNumber(t, reg, "test_gauge"))
+
+		run(errTest, "a")
+		requireSeries(t, reg, false, []string{"a", "b"}, true)
+
+		run(errTest, "a")
+		requireSeries(t, reg, false, []string{"a"}, false)
+
+		run(nil)
+		requireSeries(t, reg, false, []string{"a"}, true)
+	})
+
+	t.Run("mark", func(t *testing.T) {
+		// Age gauges mark stale series, so they're exported anyway.
+		reg, run := newStale(t, StalePolicy{Mode: StaleMark})
+
+		run(nil, "a", "b")
+
+		for range 5 {
+			run(errTest)
+		}
+
+		requireSeries(t, reg, true, []string{"a", "b"}, true)
+		require.EqualValues(t, 2, staleNumber(t, reg, "test_vec"))
+
+		run(nil, "a", "b")
+		requireSeries(t, reg, true, []string{"a", "b"}, true)
+		require.EqualValues(t, 0, staleNumber(t, reg, "test_vec"))
+	})
+}
@@ -0,0 +1,164 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+)
+
+// cacheState identifies the chain state cached responses are valid for.
+type cacheState struct {
+	endpoint *endpoint
+	height   uint32
+}
+
+// responseCache keeps JSON-encoded responses received at the same block
+// height of the same endpoint. Responses are decoded on every hit, so callers
+// never share slices, pointers or stack items and are free to modify them.
+type responseCache struct {
+	mu      sync.Mutex
+	state   cacheState
+	entries map[string][]byte
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: make(map[string][]byte)}
+}
+
+// get returns the response cached for the given state, all responses are
+// dropped if the state has changed.
+func (c *responseCache) get(state cacheState, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state != state {
+		c.state = state
+		clear(c.entries)
+
+		return nil, false
+	}
+
+	v, ok := c.entries[key]
+
+	return v, ok
+}
+
+// put stores the response received at the given state, it's ignored if the
+// state has changed meanwhile.
+func (c *responseCache) put(state cacheState, key string, v []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == state {
+		c.entries[key] = v
+	}
+}
+
+// cacheKey returns the key of the request with the given method and
+// parameters, it's empty if parameters can't be serialized. Parameter types
+// are the part of the key, since values of different types (e.g. []byte and
+// its base64 string) can be serialized the same way.
+func cacheKey(method string, params ...any) string {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return ""
+	}
+
+	return method + paramTypes(params) + string(data)
+}
+
+// paramTypes returns Go types of the parameters including the types of
+// nested parameter lists.
+func paramTypes(params []any) string {
+	var b strings.Builder
+
+	b.WriteByte('(')
+
+	for i, p := range params {
+		if i != 0 {
+			b.WriteByte(',')
+		}
+
+		if nested, ok := p.([]any); ok {
+			b.WriteString(paramTypes(nested))
+			continue
+		}
+
+		fmt.Fprintf(&b, "%T", p)
+	}
+
+	b.WriteByte(')')
+
+	return b.String()
+}
+
+// cacheState returns the current endpoint and its height. The height of
+// WebSocket endpoints is kept up to date by new block notifications, the
+// others are asked for it, since health checks update it only once in a
+// recheck interval. Zero height means the state is unknown and responses must
+// not be cached.
+func (p *Pool) cacheState(ctx context.Context) cacheState {
+	ep, conn := p.currentConn()
+	if conn == nil {
+		return cacheState{}
+	}
+
+	if p.subscribed(ep) {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+
+		return cacheState{endpoint: ep, height: ep.height}
+	}
+
+	height, err := call(ctx, p, func() (uint32, error) {
+		return conn.GetBlockCount()
+	})
+	if err != nil {
+		return cacheState{}
+	}
+
+	return cacheState{endpoint: ep, height: height}
+}
+
+// cached works like do, but returns the response cached for the current
+// block height if the pool cache is enabled. Every call gets its own copy of
+// the response. Responses with iterator sessions are never cached.
+func cached[T any](ctx context.Context, p *Pool, key string, f func(*rpcclient.Client) (T, error)) (T, error) {
+	if p.cache == nil || key == "" {
+		return do(ctx, p, f)
+	}
+
+	state := p.cacheState(ctx)
+	if state.height != 0 {
+		if data, ok := p.cache.get(state, key); ok {
+			var v T
+			if err := json.Unmarshal(data, &v); err == nil {
+				p.metrics.cacheHits.Inc()
+				return v, nil
+			}
+		}
+	}
+
+	p.metrics.cacheMisses.Inc()
+
+	res, err := do(ctx, p, f)
+	if err != nil || state.height == 0 {
+		return res, err
+	}
+
+	if inv, ok := any(res).(*result.Invoke); ok && inv.Session != (uuid.UUID{}) {
+		return res, err
+	}
+
+	if data, err := json.Marshal(res); err == nil {
+		p.cache.put(state, key, data)
+	}
+
+	return res, err
+}
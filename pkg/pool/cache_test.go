@@ -0,0 +1,124 @@
+package pool
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/internal/chaintest"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativeids"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	var (
+		c     = newResponseCache()
+		ep    = new(endpoint)
+		state = cacheState{endpoint: ep, height: 10}
+		key   = cacheKey("invokefunction", util.Uint160{1}, "symbol")
+	)
+
+	_, ok := c.get(state, key)
+	require.False(t, ok)
+
+	c.put(state, key, []byte(`"GAS"`))
+
+	v, ok := c.get(state, key)
+	require.True(t, ok)
+	require.Equal(t, `"GAS"`, string(v))
+
+	_, ok = c.get(state, cacheKey("invokefunction", util.Uint160{2}, "symbol"))
+	require.False(t, ok)
+
+	// Responses are dropped when the chain advances.
+	next := cacheState{endpoint: ep, height: 11}
+	_, ok = c.get(next, key)
+	require.False(t, ok)
+
+	// Responses received at the previous height are not stored.
+	c.put(state, key, []byte(`"GAS"`))
+	_, ok = c.get(next, key)
+	require.False(t, ok)
+
+	// Responses are dropped when the endpoint changes.
+	c.put(next, key, []byte(`"GAS"`))
+	_, ok = c.get(cacheState{endpoint: new(endpoint), height: 11}, key)
+	require.False(t, ok)
+}
+
+func TestCacheKey(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+
+	require.NotEqual(t, cacheKey("invokefunction", h), cacheKey("invokefunction", h.StringLE()))
+	require.NotEqual(t, cacheKey("invokefunction", []byte{1, 2}), cacheKey("invokefunction", "AQI="))
+	require.NotEqual(t, cacheKey("invokefunction", []any{int64(1)}), cacheKey("invokefunction", []any{"1"}))
+	require.Equal(t, cacheKey("invokefunction", h, []any{int64(1)}), cacheKey("invokefunction", h, []any{int64(1)}))
+}
+
+func TestPoolCache(t *testing.T) {
+	var (
+		ctx   = t.Context()
+		chain = chaintest.New(t)
+	)
+
+	p, err := NewPool(ctx, PrmPool{
+		Endpoints:       []Endpoint{{Address: chain.Address}},
+		DialTimeout:     time.Second,
+		RecheckInterval: time.Hour,
+		Cache:           true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, p.Close(context.Background())) })
+
+	committee, err := p.GetCommittee(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, committee)
+
+	expected := slices.Clone(committee)
+
+	// Changes made by one caller are not visible to the others.
+	committee[0] = nil
+
+	cachedCommittee, err := p.GetCommittee(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, cachedCommittee)
+
+	contract, err := p.GetContractStateByID(ctx, nativeids.NeoToken)
+	require.NoError(t, err)
+
+	cachedContract, err := p.GetContractStateByID(ctx, nativeids.NeoToken)
+	require.NoError(t, err)
+	require.Equal(t, contract, cachedContract)
+	require.NotSame(t, contract, cachedContract)
+
+	inv, err := p.Call(ctx, contract.Hash, "symbol")
+	require.NoError(t, err)
+
+	cachedInv, err := p.Call(ctx, contract.Hash, "symbol")
+	require.NoError(t, err)
+	require.Equal(t, inv, cachedInv)
+	require.NotSame(t, inv, cachedInv)
+
+	require.Equal(t, 3., testutil.ToFloat64(p.metrics.cacheHits))
+
+	// HTTP endpoints don't notify about new blocks, responses are dropped
+	// once the chain advances anyway.
+	acc := util.Uint160{1}
+
+	balance, err := p.Call(ctx, nativehashes.GasToken, "balanceOf", acc)
+	require.NoError(t, err)
+	require.Equal(t, vmstate.Halt.String(), balance.State)
+	require.Zero(t, balance.Stack[0].Value().(*big.Int).Int64())
+
+	chain.TransferGAS(t, acc, 1)
+
+	balance, err = p.Call(ctx, nativehashes.GasToken, "balanceOf", acc)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, balance.Stack[0].Value().(*big.Int).Int64())
+}
@@ -0,0 +1,225 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+)
+
+// PeerDiscovery configures discovery of RPC endpoints among peers of the
+// current RPC node.
+type PeerDiscovery struct {
+	// Template is the address of discovered endpoint, "{host}" is replaced
+	// with the peer IP address, e.g. "http://{host}:30333". Empty template
+	// disables discovery.
+	Template string
+	// Priority is the priority of discovered endpoints.
+	Priority int
+	// Interval is the time between peer list requests.
+	Interval time.Duration
+}
+
+// defaultDiscoveryInterval is the time between peer list requests used if
+// not configured.
+const defaultDiscoveryInterval = time.Minute
+
+// SetEndpoints replaces configured endpoints of the pool. Endpoints with the
+// same address and settings keep their connections, connections of removed
+// endpoints are closed. Discovered endpoints are kept.
+func (p *Pool) SetEndpoints(endpoints []Endpoint) error {
+	if len(endpoints) == 0 {
+		return errors.New("no endpoints")
+	}
+
+	p.mu.Lock()
+	p.configured = slices.Clone(endpoints)
+	p.applyEndpoints()
+	p.mu.Unlock()
+
+	p.recheck(p.ctx)
+	p.resubscribe()
+
+	return nil
+}
+
+// AddEndpoint adds endpoint to the pool, it replaces the configured endpoint
+// with the same address.
+func (p *Pool) AddEndpoint(ep Endpoint) {
+	p.mu.Lock()
+	p.configured = slices.DeleteFunc(p.configured, func(e Endpoint) bool {
+		return e.Address == ep.Address
+	})
+	p.configured = append(p.configured, ep)
+	p.applyEndpoints()
+	p.mu.Unlock()
+
+	p.recheck(p.ctx)
+	p.resubscribe()
+}
+
+// RemoveEndpoint removes configured endpoint with the given address from the
+// pool closing its connection. The last endpoint can't be removed.
+func (p *Pool) RemoveEndpoint(address string) error {
+	p.mu.Lock()
+
+	configured := slices.DeleteFunc(slices.Clone(p.configured), func(e Endpoint) bool {
+		return e.Address == address
+	})
+	if len(configured) == 0 {
+		p.mu.Unlock()
+		return errors.New("can't remove the last endpoint")
+	}
+
+	p.configured = configured
+	p.applyEndpoints()
+	p.mu.Unlock()
+
+	p.recheck(p.ctx)
+	p.resubscribe()
+
+	return nil
+}
+
+// applyEndpoints updates pool endpoints according to configured and
+// discovered ones. Must be called with p.mu held.
+func (p *Pool) applyEndpoints() {
+	var (
+		wanted  = slices.Clone(p.configured)
+		current = p.endpoints[p.current]
+		old     = make(map[string]*endpoint, len(p.endpoints))
+		updated = make([]*endpoint, 0, len(wanted)+len(p.discovered))
+	)
+
+	for _, ep := range p.discovered {
+		if !slices.ContainsFunc(wanted, func(e Endpoint) bool { return e.Address == ep.Address }) {
+			wanted = append(wanted, ep)
+		}
+	}
+
+	for _, ep := range p.endpoints {
+		old[ep.Address] = ep
+	}
+
+	for _, ep := range wanted {
+		if e, ok := old[ep.Address]; ok && sameTransport(e.Endpoint, ep) {
+			e.Priority = ep.Priority
+			updated = append(updated, e)
+			delete(old, ep.Address)
+
+			continue
+		}
+
+		e, err := newEndpoint(ep)
+		if err != nil {
+			log.Printf("endpoint %s: %v", ep.Address, err)
+			continue
+		}
+
+		updated = append(updated, e)
+	}
+
+	if len(updated) == 0 {
+		log.Printf("no valid endpoints, keep the current ones")
+		return
+	}
+
+	for _, ep := range old {
+		log.Printf("removing Neo node %s", ep.Address)
+		ep.close()
+		p.metrics.deleteEndpoint(ep.Address)
+	}
+
+	p.endpoints = updated
+	p.current = slices.Index(updated, current)
+
+	if p.current < 0 {
+		p.current = 0
+		// Select the endpoint before the next request.
+		atomic.StoreInt64(&p.lastHealthyTimestamp, 0)
+	}
+}
+
+// sameTransport checks whether endpoints can share the connection.
+func sameTransport(a, b Endpoint) bool {
+	return reflect.DeepEqual(a.TLS, b.TLS) && reflect.DeepEqual(a.Header, b.Header) && reflect.DeepEqual(a.Proxy, b.Proxy)
+}
+
+// discover replaces discovered endpoints with peers of the current node.
+func (p *Pool) discover(ctx context.Context) {
+	peers, err := do(ctx, p, func(conn *rpcclient.Client) (*result.GetPeers, error) {
+		return conn.GetPeers()
+	})
+	if err != nil {
+		log.Printf("discover Neo nodes: %v", err)
+		return
+	}
+
+	var discovered = make([]Endpoint, 0, len(peers.Connected))
+
+	for _, peer := range peers.Connected {
+		host := peer.Address
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+
+		address := strings.ReplaceAll(p.discovery.Template, "{host}", host)
+		if slices.ContainsFunc(discovered, func(e Endpoint) bool { return e.Address == address }) {
+			continue
+		}
+
+		discovered = append(discovered, Endpoint{
+			Address:  address,
+			Priority: p.discovery.Priority,
+		})
+	}
+
+	p.mu.Lock()
+
+	if slices.Equal(addresses(discovered), addresses(p.discovered)) {
+		p.mu.Unlock()
+		return
+	}
+
+	log.Printf("discovered %d Neo nodes", len(discovered))
+
+	p.discovered = discovered
+	p.applyEndpoints()
+	p.mu.Unlock()
+
+	p.recheck(ctx)
+	p.resubscribe()
+}
+
+func addresses(endpoints []Endpoint) []string {
+	res := make([]string, 0, len(endpoints))
+	for _, ep := range endpoints {
+		res = append(res, ep.Address)
+	}
+
+	return res
+}
+
+// newEndpoint creates pooled endpoint starting its proxy if needed.
+func newEndpoint(ep Endpoint) (*endpoint, error) {
+	var e = &endpoint{Endpoint: ep}
+
+	if needsProxy(ep) {
+		proxy, err := newEndpointProxy(ep)
+		if err != nil {
+			return nil, fmt.Errorf("start proxy: %w", err)
+		}
+
+		e.proxy = proxy
+	}
+
+	return e, nil
+}
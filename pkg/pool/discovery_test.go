@@ -0,0 +1,51 @@
+package pool
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEndpoints(t *testing.T) {
+	var (
+		a = &endpoint{Endpoint: Endpoint{Address: "http://a"}}
+		b = &endpoint{Endpoint: Endpoint{Address: "http://b"}}
+		p = &Pool{endpoints: []*endpoint{a, b}, current: 1, metrics: newMetrics()}
+	)
+
+	p.configured = []Endpoint{{Address: "http://c"}, {Address: "http://b", Priority: 1}}
+	p.applyEndpoints()
+
+	require.Len(t, p.endpoints, 2)
+	require.Equal(t, "http://c", p.endpoints[0].Address)
+	// Unchanged endpoint keeps its state.
+	require.Same(t, b, p.endpoints[1])
+	require.Equal(t, 1, b.Priority)
+	require.Equal(t, 1, p.current)
+
+	p.discovered = []Endpoint{{Address: "http://d"}, {Address: "http://c"}}
+	p.applyEndpoints()
+
+	require.Equal(t, []string{"http://c", "http://b", "http://d"}, addresses(endpointsOf(p)))
+
+	// Endpoint with new transport settings is recreated.
+	p.configured = []Endpoint{{Address: "http://b", Header: http.Header{"X-Test": {"1"}}}}
+	p.discovered = nil
+	p.applyEndpoints()
+	t.Cleanup(p.closeEndpoints)
+
+	require.Len(t, p.endpoints, 1)
+	require.NotSame(t, b, p.endpoints[0])
+	require.NotNil(t, p.endpoints[0].proxy)
+	require.Equal(t, 0, p.current)
+}
+
+func endpointsOf(p *Pool) []Endpoint {
+	res := make([]Endpoint, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		res = append(res, ep.Endpoint)
+	}
+
+	return res
+}
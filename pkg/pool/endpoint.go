@@ -0,0 +1,127 @@
+package pool
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+)
+
+type (
+	// Endpoint describes Neo RPC node to connect to.
+	Endpoint struct {
+		// Address is the RPC node URL, ws:// and wss:// schemes make pool use
+		// WebSocket client.
+		Address string
+		// Priority is the group of the endpoint for [NewPriorityStrategy],
+		// endpoints with lower values are preferred.
+		Priority int
+		// TLS is the configuration of secure connections to the endpoint,
+		// default one is used if not set.
+		TLS *tls.Config
+		// Header is added to every request to the endpoint including
+		// WebSocket handshake.
+		Header http.Header
+		// Proxy is the URL of HTTP(S) or SOCKS5 proxy to connect to the
+		// endpoint through, environment settings are used if not set.
+		Proxy *url.URL
+	}
+
+	// EndpointInfo describes the endpoint state used by [Strategy] to order
+	// endpoints.
+	EndpointInfo struct {
+		Endpoint
+		// Healthy is true if the endpoint is connected and is not lagging.
+		Healthy bool
+		// Height is the last known block count, zero if unknown.
+		Height uint32
+		// Latency is the smoothed response time of health checks, zero if
+		// unknown.
+		Latency time.Duration
+	}
+
+	// endpoint is the pooled endpoint state.
+	endpoint struct {
+		Endpoint
+
+		client  *client
+		proxy   *endpointProxy
+		height  uint32
+		latency time.Duration
+
+		// failures is the number of consecutive failed requests.
+		failures  int
+		openUntil time.Time
+	}
+)
+
+// String implements [fmt.Stringer].
+func (e Endpoint) String() string {
+	return e.Address
+}
+
+// breakerOpen checks whether the endpoint circuit breaker is open. Must be
+// called with p.mu held.
+func (e *endpoint) breakerOpen() bool {
+	return time.Now().Before(e.openUntil)
+}
+
+// dialAddress returns the address to connect RPC client to.
+func (e *endpoint) dialAddress() string {
+	if e.proxy != nil {
+		return e.proxy.address
+	}
+
+	return e.Address
+}
+
+// close releases the endpoint connection and its proxy.
+func (e *endpoint) close() {
+	if e.client != nil {
+		e.client.close()
+		e.client = nil
+	}
+
+	if e.proxy != nil {
+		e.proxy.close()
+	}
+}
+
+// latencySmoothing is the weight of the last measurement in the endpoint
+// latency.
+const latencySmoothing = 0.3
+
+// check requests the current block count from the endpoint and updates its
+// state. Must be called with p.mu held.
+func (e *endpoint) check() error {
+	height, latency, err := checkHeight(e.client.Client)
+	e.update(height, latency, err)
+
+	return err
+}
+
+// update sets the result of the endpoint health check. Must be called with
+// p.mu held.
+func (e *endpoint) update(height uint32, latency time.Duration, err error) {
+	if err != nil {
+		e.height = 0
+		return
+	}
+
+	e.height = height
+
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(e.latency))
+	}
+}
+
+func checkHeight(cl *rpcclient.Client) (uint32, time.Duration, error) {
+	start := time.Now()
+	height, err := cl.GetBlockCount()
+
+	return height, time.Since(start), err
+}
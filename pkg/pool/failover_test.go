@@ -0,0 +1,387 @@
+package pool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/pkg/rpctest"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var testErr = neorpc.NewInternalServerError("test")
+
+type testNode struct {
+	*rpctest.Node
+	faults  *rpctest.FaultProxy
+	address string
+}
+
+func newTestNode(t *testing.T, height uint32, wrap func(http.Handler) http.Handler) *testNode {
+	var (
+		node   = rpctest.NewNode(height)
+		faults = rpctest.NewFaultProxy(node)
+		h      = http.Handler(faults)
+	)
+
+	if wrap != nil {
+		h = wrap(faults)
+	}
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	// Stalled requests must be released before the server is closed.
+	t.Cleanup(faults.Reset)
+
+	return &testNode{Node: node, faults: faults, address: srv.URL}
+}
+
+func newTestPool(t *testing.T, prm PrmPool, nodes ...*testNode) *Pool {
+	for i, n := range nodes {
+		prm.Endpoints = append(prm.Endpoints, Endpoint{Address: n.address, Priority: i})
+	}
+
+	prm.DialTimeout = time.Second
+	prm.RecheckInterval = time.Hour
+
+	if prm.CallTimeout == 0 {
+		prm.CallTimeout = time.Second
+	}
+
+	p, err := NewPool(t.Context(), prm)
+	require.NoError(t, err)
+
+	return p
+}
+
+func requireCurrent(t *testing.T, p *Pool, n *testNode) {
+	ep, _ := p.currentConn()
+	require.Equal(t, n.address, ep.Address)
+}
+
+func TestFailover(t *testing.T) {
+	var (
+		ctx = t.Context()
+		a   = newTestNode(t, 100, nil)
+		b   = newTestNode(t, 200, nil)
+		p   = newTestPool(t, PrmPool{
+			CallTimeout: 300 * time.Millisecond,
+			Retry:       RetryPolicy{Attempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		}, a, b)
+	)
+
+	for _, tc := range []struct {
+		name  string
+		fault rpctest.Fault
+		// slow endpoint can exhaust the call timeout, so the request may
+		// fail before the pool switches to another endpoint.
+		slow bool
+	}{
+		{name: "drop", fault: rpctest.Fault{Drop: true}},
+		{name: "stall", fault: rpctest.Fault{Stall: true}, slow: true},
+		{name: "latency", fault: rpctest.Fault{Latency: time.Second}, slow: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			height, err := p.GetBlockCount(ctx)
+			require.NoError(t, err)
+			require.EqualValues(t, 100, height)
+
+			a.faults.Set(tc.fault)
+
+			height, err = p.GetBlockCount(ctx)
+			if err != nil && tc.slow {
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+
+				height, err = p.GetBlockCount(ctx)
+			}
+
+			require.NoError(t, err)
+			require.EqualValues(t, 200, height)
+			requireCurrent(t, p, b)
+
+			// The pool returns to the preferred endpoint once it recovers.
+			a.faults.Reset()
+			p.recheck(ctx)
+			requireCurrent(t, p, a)
+		})
+	}
+
+	t.Run("server error", func(t *testing.T) {
+		a.faults.Set(rpctest.Fault{Error: testErr})
+		t.Cleanup(a.faults.Reset)
+
+		_, err := p.GetBlockCount(ctx)
+		require.ErrorIs(t, err, testErr)
+
+		// Node is alive, so the request is neither retried nor switched.
+		requireCurrent(t, p, a)
+	})
+
+	t.Run("all down", func(t *testing.T) {
+		a.faults.Set(rpctest.Fault{Drop: true})
+		b.faults.Set(rpctest.Fault{Drop: true})
+		t.Cleanup(b.faults.Reset)
+		t.Cleanup(a.faults.Reset)
+
+		_, err := p.GetBlockCount(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestRecheck(t *testing.T) {
+	var (
+		ctx = t.Context()
+		a   = newTestNode(t, 100, nil)
+		b   = newTestNode(t, 100, nil)
+		p   = newTestPool(t, PrmPool{MaxHeightLag: 5}, a, b)
+	)
+
+	requireCurrent(t, p, a)
+
+	t.Run("stale height", func(t *testing.T) {
+		a.faults.SetFor("getblockcount", rpctest.Fault{Height: 90})
+
+		p.recheck(ctx)
+		requireCurrent(t, p, b)
+
+		a.faults.Reset()
+
+		p.recheck(ctx)
+		requireCurrent(t, p, a)
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		a.faults.Set(rpctest.Fault{Drop: true})
+
+		p.recheck(ctx)
+		requireCurrent(t, p, b)
+
+		a.faults.Reset()
+
+		p.recheck(ctx)
+		requireCurrent(t, p, a)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		p.strategy = NewLatencyStrategy()
+		a.faults.Set(rpctest.Fault{Latency: 50 * time.Millisecond})
+		t.Cleanup(a.faults.Reset)
+
+		for range 5 {
+			p.recheck(ctx)
+		}
+
+		requireCurrent(t, p, b)
+	})
+
+	t.Run("all unavailable", func(t *testing.T) {
+		require.True(t, p.Healthy())
+
+		a.faults.Set(rpctest.Fault{Drop: true})
+		b.faults.Set(rpctest.Fault{Drop: true})
+
+		p.recheck(ctx)
+		require.False(t, p.Healthy())
+
+		a.faults.Reset()
+		b.faults.Reset()
+
+		p.recheck(ctx)
+		require.True(t, p.Healthy())
+	})
+}
+
+func TestLaggingSwitch(t *testing.T) {
+	var (
+		ctx = t.Context()
+		a   = newTestNode(t, 100, nil)
+		b   = newTestNode(t, 100, nil)
+		p   = newTestPool(t, PrmPool{MaxHeightLag: 5}, a, b)
+	)
+
+	requireCurrent(t, p, a)
+
+	p.mu.Lock()
+	var (
+		lagging    = p.endpoints[0]
+		conn       = lagging.client
+		reconnects = testutil.ToFloat64(p.metrics.reconnects.WithLabelValues(a.address))
+	)
+
+	// The pool learns about the new height of b from notifications.
+	b.SetHeight(110)
+	p.endpoints[1].height = 110
+	p.mu.Unlock()
+
+	atomic.StoreInt64(&p.lastHealthyTimestamp, 0)
+
+	height, err := p.GetBlockCount(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 110, height)
+	requireCurrent(t, p, b)
+
+	// Lagging endpoint is alive, so it's neither reset nor redialed.
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	require.EqualValues(t, 100, lagging.height)
+	require.Same(t, conn, lagging.client)
+	require.Equal(t, reconnects, testutil.ToFloat64(p.metrics.reconnects.WithLabelValues(a.address)))
+}
+
+func TestIterateFailover(t *testing.T) {
+	var (
+		ctx      = t.Context()
+		p        *Pool
+		a        *testNode
+		switched atomic.Bool
+	)
+
+	a = newTestNode(t, 100, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Switch the pool to another endpoint after the first page is read.
+			if a.Calls("traverseiterator") == 1 && switched.CompareAndSwap(false, true) {
+				a.faults.SetFor("getblockcount", rpctest.Fault{Height: 1})
+				p.recheck(ctx)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	})
+	b := newTestNode(t, 100, nil)
+
+	p = newTestPool(t, PrmPool{MaxHeightLag: 5}, a, b)
+
+	a.SetItems(testItems(0, 250))
+	b.SetItems(testItems(1000, 250))
+
+	items, err := p.Iterate(ctx, util.Uint160{}, "list")
+	require.NoError(t, err)
+	require.Equal(t, testItems(0, 250), items)
+	require.True(t, switched.Load())
+
+	requireCurrent(t, p, b)
+	require.Zero(t, b.Calls("traverseiterator"))
+	require.Zero(t, a.Sessions())
+}
+
+func TestIterateFault(t *testing.T) {
+	var (
+		ctx = t.Context()
+		a   = newTestNode(t, 100, nil)
+		p   = newTestPool(t, PrmPool{}, a)
+	)
+
+	a.SetItems(testItems(0, 250))
+	a.faults.SetFor("traverseiterator", rpctest.Fault{Error: testErr})
+
+	_, err := p.Iterate(ctx, util.Uint160{}, "list")
+	require.ErrorIs(t, err, testErr)
+
+	// Session is terminated even if the iterator can't be read.
+	require.Zero(t, a.Sessions())
+}
+
+func TestIterateExpand(t *testing.T) {
+	var (
+		ctx = t.Context()
+		a   = newTestNode(t, 100, nil)
+		p   = newTestPool(t, PrmPool{}, a)
+	)
+
+	a.DisableSessions()
+
+	// Iterator of exactly max items fits into the expanded script.
+	a.SetItems(testItems(0, maxExpandedIteratorItems))
+
+	items, err := p.Iterate(ctx, util.Uint160{}, "list")
+	require.NoError(t, err)
+	require.Equal(t, testItems(0, maxExpandedIteratorItems), items)
+
+	a.SetItems(testItems(0, maxExpandedIteratorItems+1))
+
+	_, err = p.Iterate(ctx, util.Uint160{}, "list")
+	require.ErrorContains(t, err, "RPC node sessions are required")
+}
+
+func TestSessionCallsUnavailable(t *testing.T) {
+	var (
+		ctx = t.Context()
+		a   = newTestNode(t, 100, nil)
+		p   = newTestPool(t, PrmPool{}, a)
+	)
+
+	a.SetItems(testItems(0, 10))
+
+	res, err := p.Call(ctx, util.Uint160{}, "list")
+	require.NoError(t, err)
+
+	sid, iter, err := unwrap.SessionIterator(res, nil)
+	require.NoError(t, err)
+
+	// Session calls fail without connected endpoints instead of using the
+	// missing connection.
+	a.faults.Set(rpctest.Fault{Drop: true})
+	p.recheck(ctx)
+	require.False(t, p.Healthy())
+
+	_, err = p.TraverseIterator(ctx, sid, &iter, 10)
+	require.Error(t, err)
+
+	require.Error(t, p.TerminateSession(ctx, sid))
+
+	a.faults.Reset()
+	p.recheck(ctx)
+
+	require.NoError(t, p.TerminateSession(ctx, sid))
+	require.Zero(t, a.Sessions())
+}
+
+func TestClose(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(t.Context())
+		a           = newTestNode(t, 100, nil)
+		p           = newTestPool(t, PrmPool{CallTimeout: time.Minute}, a)
+		done        = make(chan error, 1)
+	)
+
+	a.SetItems(testItems(0, 250))
+	a.faults.SetFor("traverseiterator", rpctest.Fault{Stall: true})
+
+	go func() {
+		_, err := p.Iterate(ctx, util.Uint160{}, "list")
+		done <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		p.sessionsMu.Lock()
+		defer p.sessionsMu.Unlock()
+
+		return len(p.sessions) == 1
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, p.Close(t.Context()))
+
+	// Session is terminated by Close while the iterator is being read.
+	require.Zero(t, a.Sessions())
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func testItems(start, n int) []stackitem.Item {
+	items := make([]stackitem.Item, 0, n)
+	for i := range n {
+		items = append(items, stackitem.Make(start+i))
+	}
+
+	return items
+}
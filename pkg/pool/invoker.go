@@ -0,0 +1,83 @@
+package pool
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// Invoker is the [Pool] bound to the context. It implements invoker interfaces
+// of contract wrappers which don't accept contexts, every call is limited by
+// the context and the pool call timeout.
+type Invoker struct {
+	pool *Pool
+	ctx  context.Context
+}
+
+// Invoker returns the pool view bound to ctx.
+func (p *Pool) Invoker(ctx context.Context) *Invoker {
+	return &Invoker{pool: p, ctx: ctx}
+}
+
+// Call calls [Pool.Call] with the bound context.
+func (i *Invoker) Call(contract util.Uint160, operation string, params ...any) (*result.Invoke, error) {
+	return i.pool.Call(i.ctx, contract, operation, params...)
+}
+
+// CallAndExpandIterator calls [Pool.CallAndExpandIterator] with the bound
+// context.
+func (i *Invoker) CallAndExpandIterator(contract util.Uint160, method string, maxItems int, params ...any) (*result.Invoke, error) {
+	return i.pool.CallAndExpandIterator(i.ctx, contract, method, maxItems, params...)
+}
+
+// TerminateSession calls [Pool.TerminateSession] with the bound context.
+func (i *Invoker) TerminateSession(sessionID uuid.UUID) error {
+	return i.pool.TerminateSession(i.ctx, sessionID)
+}
+
+// TraverseIterator calls [Pool.TraverseIterator] with the bound context.
+func (i *Invoker) TraverseIterator(sessionID uuid.UUID, iterator *result.Iterator, num int) ([]stackitem.Item, error) {
+	return i.pool.TraverseIterator(i.ctx, sessionID, iterator, num)
+}
+
+// GetContractStateByID calls [Pool.GetContractStateByID] with the bound
+// context.
+func (i *Invoker) GetContractStateByID(id int32) (*state.Contract, error) {
+	return i.pool.GetContractStateByID(i.ctx, id)
+}
+
+// call runs f limited by ctx and the pool call timeout. RPC client doesn't
+// accept contexts, so f is left running in background when ctx is done and
+// its result is discarded, the request itself is limited by the client
+// request timeout.
+func call[T any](ctx context.Context, p *Pool, f func() (T, error)) (T, error) {
+	if p.callTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.callTimeout)
+		defer cancel()
+	}
+
+	type res struct {
+		v   T
+		err error
+	}
+
+	var done = make(chan res, 1)
+
+	go func() {
+		v, err := f()
+		done <- res{v, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
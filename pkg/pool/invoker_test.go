@@ -0,0 +1,41 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCall(t *testing.T) {
+	var (
+		p    = &Pool{callTimeout: 10 * time.Millisecond}
+		hang = make(chan struct{})
+	)
+	defer close(hang)
+
+	v, err := call(context.Background(), p, func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+
+	_, err = call(context.Background(), p, func() (int, error) { return 0, errors.New("fail") })
+	require.EqualError(t, err, "fail")
+
+	_, err = call(context.Background(), p, func() (int, error) {
+		<-hang
+		return 1, nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p.callTimeout = 0
+	_, err = call(ctx, p, func() (int, error) {
+		<-hang
+		return 1, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,150 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+const (
+	// iteratorPageSize is the number of items requested per traverseiterator
+	// call.
+	iteratorPageSize = 100
+
+	// maxExpandedIteratorItems is the limit of items unwrapped in the VM
+	// script when RPC node has sessions disabled, it's bounded by the VM stack
+	// size anyway.
+	maxExpandedIteratorItems = 2048
+)
+
+// Iterate calls the contract method returning an iterator and reads all of
+// its items, every RPC call is limited by ctx and the pool call timeout.
+// Iterator session is bound to the node that handled the call, so all pages
+// are requested from it even if the pool switches to another endpoint
+// meanwhile, and the session is terminated afterward. If the node has
+// sessions disabled, the iterator is unwrapped in the invocation script.
+func (p *Pool) Iterate(ctx context.Context, contract util.Uint160, method string, params ...any) ([]stackitem.Item, error) {
+	type invocation struct {
+		inv *invoker.Invoker
+		res *result.Invoke
+	}
+
+	r, err := do(ctx, p, func(conn *rpcclient.Client) (invocation, error) {
+		inv := invoker.New(conn, nil)
+		res, err := inv.Call(contract, method, params...)
+
+		return invocation{inv, res}, err
+	})
+
+	inv := r.inv
+
+	sid, iter, err := unwrap.SessionIterator(r.res, err)
+	if err != nil {
+		if errors.Is(err, unwrap.ErrNoSessionID) {
+			return p.expandIterator(ctx, inv, contract, method, params...)
+		}
+
+		return nil, err
+	}
+
+	if iter.ID == nil {
+		// Sessions are disabled, but the node expands iterators in place.
+		if iter.Truncated {
+			return p.expandIterator(ctx, inv, contract, method, params...)
+		}
+
+		return iter.Values, nil
+	}
+
+	p.addSession(sid, inv)
+
+	defer func() {
+		// The session is terminated by Close if it's not found.
+		if p.removeSession(sid) {
+			p.terminateSession(context.WithoutCancel(ctx), sid, inv)
+		}
+	}()
+
+	var items []stackitem.Item
+
+	for {
+		page, err := call(ctx, p, func() ([]stackitem.Item, error) {
+			return inv.TraverseIterator(sid, &iter, iteratorPageSize)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("traverse iterator: %w", err)
+		}
+
+		if len(page) == 0 {
+			return items, nil
+		}
+
+		items = append(items, page...)
+	}
+}
+
+func (p *Pool) addSession(sid uuid.UUID, inv *invoker.Invoker) {
+	p.sessionsMu.Lock()
+	defer p.sessionsMu.Unlock()
+
+	p.sessions[sid] = inv
+}
+
+// removeSession forgets the session and reports whether it was open.
+func (p *Pool) removeSession(sid uuid.UUID) bool {
+	p.sessionsMu.Lock()
+	defer p.sessionsMu.Unlock()
+
+	_, ok := p.sessions[sid]
+	delete(p.sessions, sid)
+
+	return ok
+}
+
+// terminateSessions terminates all open iterator sessions.
+func (p *Pool) terminateSessions(ctx context.Context) {
+	p.sessionsMu.Lock()
+	sessions := p.sessions
+	p.sessions = make(map[uuid.UUID]*invoker.Invoker)
+	p.sessionsMu.Unlock()
+
+	for sid, inv := range sessions {
+		p.terminateSession(ctx, sid, inv)
+	}
+}
+
+// terminateSession terminates the session via the node that created it.
+func (p *Pool) terminateSession(ctx context.Context, sid uuid.UUID, inv *invoker.Invoker) {
+	_, err := call(ctx, p, func() (struct{}, error) {
+		return struct{}{}, inv.TerminateSession(sid)
+	})
+	if err != nil {
+		log.Printf("terminate iterator session %s: %v", sid, err)
+	}
+}
+
+func (p *Pool) expandIterator(ctx context.Context, inv *invoker.Invoker, contract util.Uint160, method string, params ...any) ([]stackitem.Item, error) {
+	// One more item is requested to tell the iterator of exactly max items
+	// from the truncated one.
+	items, err := unwrap.Array(call(ctx, p, func() (*result.Invoke, error) {
+		return inv.CallAndExpandIterator(contract, method, maxExpandedIteratorItems+1, params...)
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("expand iterator: %w", err)
+	}
+
+	if len(items) > maxExpandedIteratorItems {
+		return nil, fmt.Errorf("iterator has more than %d items, RPC node sessions are required to read it", maxExpandedIteratorItems)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,156 @@
+package pool
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "neo_exporter"
+
+	endpointLabel = "endpoint"
+)
+
+// metrics is the set of RPC metrics of a single pool, so that pools of
+// different chains (or different monitors) don't share them.
+type metrics struct {
+	endpointUp          *prometheus.GaugeVec
+	endpointActive      *prometheus.GaugeVec
+	reconnects          *prometheus.CounterVec
+	healthCheckFailures *prometheus.CounterVec
+	currentLag          *prometheus.GaugeVec
+	requestFailures     *prometheus.CounterVec
+	retries             prometheus.Counter
+	breakerOpen         *prometheus.GaugeVec
+	cacheHits           prometheus.Counter
+	cacheMisses         prometheus.Counter
+	dialDuration        *prometheus.HistogramVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		endpointUp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "rpc_endpoint_up",
+				Help:      "Whether RPC endpoint is available (1) or not (0)",
+			},
+			[]string{endpointLabel},
+		),
+		endpointActive: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "rpc_endpoint_active",
+				Help:      "Whether RPC endpoint is currently used for requests (1) or not (0)",
+			},
+			[]string{endpointLabel},
+		),
+		reconnects: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rpc_reconnects_total",
+				Help:      "Number of reconnection attempts to RPC endpoint",
+			},
+			[]string{endpointLabel},
+		),
+		healthCheckFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rpc_health_check_failures_total",
+				Help:      "Number of failed RPC endpoint health checks",
+			},
+			[]string{endpointLabel},
+		),
+		currentLag: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "rpc_active_endpoint_lag",
+				Help:      "Number of blocks the currently used RPC endpoint is behind the most up-to-date one",
+			},
+			[]string{endpointLabel},
+		),
+		requestFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rpc_request_failures_total",
+				Help:      "Number of RPC requests failed because of transport errors",
+			},
+			[]string{endpointLabel},
+		),
+		retries: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rpc_request_retries_total",
+				Help:      "Number of repeated RPC requests",
+			},
+		),
+		breakerOpen: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "rpc_circuit_breaker_open",
+				Help:      "Whether RPC endpoint circuit breaker is open (1) or not (0)",
+			},
+			[]string{endpointLabel},
+		),
+		cacheHits: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rpc_cache_hits_total",
+				Help:      "Number of RPC requests served from the response cache",
+			},
+		),
+		cacheMisses: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "rpc_cache_misses_total",
+				Help:      "Number of cacheable RPC requests sent to RPC node",
+			},
+		),
+		dialDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "rpc_dial_duration_seconds",
+				Help:      "Time spent connecting to RPC endpoint",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{endpointLabel},
+		),
+	}
+}
+
+// collectors returns all metrics of the set.
+func (m *metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.endpointUp,
+		m.endpointActive,
+		m.reconnects,
+		m.healthCheckFailures,
+		m.currentLag,
+		m.dialDuration,
+		m.requestFailures,
+		m.retries,
+		m.breakerOpen,
+		m.cacheHits,
+		m.cacheMisses,
+	}
+}
+
+// deleteEndpoint removes metrics of the endpoint that is not used anymore.
+func (m *metrics) deleteEndpoint(endpoint string) {
+	for _, vec := range []interface {
+		DeleteLabelValues(...string) bool
+	}{m.endpointUp, m.endpointActive, m.reconnects, m.healthCheckFailures, m.currentLag, m.dialDuration, m.requestFailures, m.breakerOpen} {
+		vec.DeleteLabelValues(endpoint)
+	}
+}
+
+func (m *metrics) setEndpointUp(endpoint string, up bool) {
+	m.endpointUp.WithLabelValues(endpoint).Set(boolToFloat(up))
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,45 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/internal/chaintest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolMetrics(t *testing.T) {
+	var (
+		ctx   = t.Context()
+		chain = chaintest.New(t)
+		reg   = prometheus.NewRegistry()
+		pools [2]*Pool
+	)
+
+	// Pools of different chains are registered in one registry with
+	// different labels, their metrics are independent.
+	for i := range pools {
+		p, err := NewPool(ctx, PrmPool{
+			Endpoints:       []Endpoint{{Address: chain.Address}},
+			DialTimeout:     time.Second,
+			RecheckInterval: time.Hour,
+			Cache:           i == 0,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, p.Close(context.Background())) })
+
+		prometheus.WrapRegistererWith(prometheus.Labels{"chain": string(rune('a' + i))}, reg).MustRegister(p.Metrics()...)
+
+		pools[i] = p
+	}
+
+	_, err := pools[0].GetCommittee(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, 1., testutil.ToFloat64(pools[0].metrics.cacheMisses))
+	require.Zero(t, testutil.ToFloat64(pools[1].metrics.cacheMisses))
+	require.Equal(t, 2, testutil.CollectAndCount(reg, "neo_exporter_rpc_endpoint_active"))
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/url"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-exporter/pkg/monitor"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
@@ -20,84 +23,223 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	rpcnns "github.com/nspcc-dev/neofs-contract/rpc/nns"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // Pool represent virtual connection to the Neo network to communicate
 // with multiple Neo servers.
 type Pool struct {
-	ctx     context.Context
-	mu      sync.RWMutex
-	clients []*rpcclient.Client
-	opts    rpcclient.Options
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.RWMutex
+	opts     rpcclient.Options
+	strategy Strategy
+
+	subMu sync.Mutex
+	sub   *subscription
+
+	sessionsMu sync.Mutex
+	// sessions are open iterator sessions by their IDs, they're terminated
+	// on Close.
+	sessions map[uuid.UUID]*invoker.Invoker
 
 	lastHealthyTimestamp int64
 	recheckInterval      time.Duration
 
-	current, next int
-	endpoints     []string
+	current   int
+	endpoints []*endpoint
+	// configured endpoints are set by the user, discovered ones are found
+	// via RPC nodes peers.
+	configured   []Endpoint
+	discovered   []Endpoint
+	discovery    PeerDiscovery
+	maxHeightLag uint32
+	callTimeout  time.Duration
+	retry        RetryPolicy
+	breaker      BreakerPolicy
+	cache        *responseCache
+	metrics      *metrics
 }
 
 // PrmPool groups parameter to create Pool.
 type PrmPool struct {
-	Endpoints       []string
+	Endpoints       []Endpoint
 	DialTimeout     time.Duration
 	RecheckInterval time.Duration
+	// MaxHeightLag is the number of blocks endpoint can be behind the most
+	// up-to-date one to be considered healthy. Zero disables the check.
+	MaxHeightLag uint32
+	// Strategy defines the order of endpoint selection, [NewPriorityStrategy]
+	// is used if not set.
+	Strategy Strategy
+	// CallTimeout limits every RPC call made by the pool including
+	// switching to another endpoint if the current one fails. Zero means no
+	// limit except for the call context.
+	CallTimeout time.Duration
+	// Retry defines how requests failed because of transport errors are
+	// repeated, they are not repeated by default.
+	Retry RetryPolicy
+	// Breaker defines when the endpoint is considered unhealthy because of
+	// failed requests, it's disabled by default.
+	Breaker BreakerPolicy
+	// Cache enables caching of read-only requests until the block height of
+	// the current endpoint changes.
+	Cache bool
+	// Discovery enables adding peers of RPC nodes to the pool, it's disabled
+	// by default.
+	Discovery PeerDiscovery
 }
 
 // defaultRecheckInterval stores the interval after which a connection health check is performed.
 const defaultRecheckInterval = 5 * time.Second
 
-// NewPool creates connection pool using parameters.
+// NewPool creates connection pool using parameters. The pool works until ctx
+// is done or Close is called.
 func NewPool(ctx context.Context, prm PrmPool) (*Pool, error) {
 	recheck := prm.RecheckInterval
 	if recheck <= 0 {
 		recheck = defaultRecheckInterval
 	}
 
+	strategy := prm.Strategy
+	if strategy == nil {
+		strategy = NewPriorityStrategy()
+	}
+
 	pool := &Pool{
-		ctx:             ctx,
-		endpoints:       prm.Endpoints,
+		endpoints:       make([]*endpoint, 0, len(prm.Endpoints)),
+		configured:      slices.Clone(prm.Endpoints),
+		discovery:       prm.Discovery,
 		recheckInterval: recheck,
-		opts:            rpcclient.Options{DialTimeout: prm.DialTimeout},
-		clients:         make([]*rpcclient.Client, len(prm.Endpoints)),
+		opts:            rpcclient.Options{DialTimeout: prm.DialTimeout, RequestTimeout: prm.CallTimeout},
+		maxHeightLag:    prm.MaxHeightLag,
+		strategy:        strategy,
+		callTimeout:     prm.CallTimeout,
+		retry:           prm.Retry,
+		breaker:         prm.Breaker,
+		sessions:        make(map[uuid.UUID]*invoker.Invoker),
+		metrics:         newMetrics(),
+	}
+
+	if prm.Cache {
+		pool.cache = newResponseCache()
+	}
+
+	for _, ep := range prm.Endpoints {
+		e, err := newEndpoint(ep)
+		if err != nil {
+			pool.closeEndpoints()
+			return nil, fmt.Errorf("endpoint %s: %w", ep.Address, err)
+		}
+
+		pool.endpoints = append(pool.endpoints, e)
 	}
 
 	if err := pool.dial(ctx); err != nil {
+		pool.closeEndpoints()
 		return nil, err
 	}
 
-	go func() {
-		tick := time.NewTicker(recheck)
+	ctx, pool.cancel = context.WithCancel(ctx)
+	pool.ctx = ctx
+
+	pool.recheck(ctx)
+	pool.resubscribe()
+
+	pool.wg.Go(func() {
+		var (
+			tick     = time.NewTicker(recheck)
+			discover <-chan time.Time
+		)
+
+		if pool.discovery.Template != "" {
+			interval := pool.discovery.Interval
+			if interval <= 0 {
+				interval = defaultDiscoveryInterval
+			}
+
+			discoverTick := time.NewTicker(interval)
+			defer discoverTick.Stop()
+
+			discover = discoverTick.C
+
+			pool.discover(ctx)
+		}
 
 		for {
 			select {
 			case <-tick.C:
 				pool.recheck(ctx)
+				pool.resubscribe()
+			case <-discover:
+				pool.discover(ctx)
 			case <-ctx.Done():
 				tick.Stop()
+
+				pool.subMu.Lock()
+				if pool.sub != nil {
+					pool.sub.stop()
+				}
+				pool.subMu.Unlock()
+
+				pool.mu.Lock()
+				pool.closeEndpoints()
+				pool.mu.Unlock()
 				return
 			}
 		}
-	}()
+	})
 
 	return pool, nil
 }
 
+// Metrics returns RPC metrics of the pool. They are not registered anywhere,
+// the user registers them along with other metrics of the chain.
+func (p *Pool) Metrics() []prometheus.Collector {
+	return p.metrics.collectors()
+}
+
+// Close terminates open iterator sessions, stops background checks and
+// closes connections. It waits for background goroutines until ctx is done.
+func (p *Pool) Close(ctx context.Context) error {
+	p.terminateSessions(ctx)
+	p.cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeEndpoints closes connections and proxies of all endpoints.
+func (p *Pool) closeEndpoints() {
+	for _, ep := range p.endpoints {
+		ep.close()
+	}
+}
+
 func (p *Pool) dial(ctx context.Context) error {
-	var (
-		opts             = rpcclient.Options{DialTimeout: p.opts.DialTimeout}
-		hasHealthyClient bool
-	)
+	var hasHealthyClient bool
 
-	for i, ep := range p.endpoints {
-		neoClient, err := neoGoClient(ctx, ep, opts)
+	for _, ep := range p.endpoints {
+		neoClient, err := p.connect(ctx, ep)
 		if err != nil {
-			log.Printf("endpoint %s is not healthy: %s", ep, err)
+			log.Printf("endpoint %s is not healthy: %s", ep.Address, err)
 			continue
 		}
 
 		hasHealthyClient = true
-		p.clients[i] = neoClient
+		ep.client = neoClient
 	}
 
 	if !hasHealthyClient {
@@ -107,215 +249,440 @@ func (p *Pool) dial(ctx context.Context) error {
 	return nil
 }
 
+// recheck checks all endpoints reconnecting to the failed ones and switches
+// to the most preferred healthy endpoint according to the pool strategy.
 func (p *Pool) recheck(ctx context.Context) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	for i := range p.endpoints {
-		var (
-			cl  = p.clients[i]
-			err error
-		)
+	for _, ep := range p.endpoints {
+		var err error
 
-		if cl != nil {
-			_, err = cl.GetBlockCount()
+		if ep.client != nil {
+			err = ep.check()
+			if err != nil {
+				p.metrics.healthCheckFailures.WithLabelValues(ep.Address).Inc()
+			}
 		}
-		if cl == nil || err != nil {
-			p.clients[i], err = neoGoClient(ctx, p.endpoints[i], p.opts)
+		if ep.client == nil || err != nil {
+			if ep.client != nil {
+				ep.client.close()
+			}
+
+			p.metrics.reconnects.WithLabelValues(ep.Address).Inc()
+			ep.client, err = p.connect(ctx, ep)
 			if err != nil {
-				log.Printf("reconnect to Neo node %s failed: %v", p.endpoints[i], err)
+				log.Printf("reconnect to Neo node %s failed: %v", ep.Address, err)
+				continue
 			}
 
+			err = ep.check()
+		}
+
+		p.metrics.setEndpointUp(ep.Address, err == nil)
+		p.metrics.breakerOpen.WithLabelValues(ep.Address).Set(boolToFloat(ep.breakerOpen()))
+	}
+
+	for _, index := range p.strategy.Order(p.current, p.endpointInfos()) {
+		if !p.isHealthy(index) {
 			continue
 		}
+
+		if index != p.current {
+			log.Printf("switching from Neo node %s to %s", p.endpoints[p.current].Address, p.endpoints[index].Address)
+			atomic.StoreInt64(&p.lastHealthyTimestamp, time.Now().UTC().UnixNano())
+		}
+
+		p.setCurrent(index)
+
+		break
 	}
+
+	p.updateLag()
 }
 
-func (p *Pool) isCurrentHealthy() bool {
-	if (time.Now().UTC().UnixNano() - atomic.LoadInt64(&p.lastHealthyTimestamp)) < p.recheckInterval.Nanoseconds() {
-		return true
-	}
+// endpointInfos returns the state of endpoints for the pool strategy. Must be
+// called with p.mu held.
+func (p *Pool) endpointInfos() []EndpointInfo {
+	infos := make([]EndpointInfo, 0, len(p.endpoints))
 
-	conn := p.conn()
-	if conn == nil {
-		return false
+	for i, ep := range p.endpoints {
+		infos = append(infos, EndpointInfo{
+			Endpoint: ep.Endpoint,
+			Healthy:  p.isHealthy(i),
+			Height:   ep.height,
+			Latency:  ep.latency,
+		})
 	}
 
-	if _, err := conn.GetBlockCount(); err == nil {
-		atomic.StoreInt64(&p.lastHealthyTimestamp, time.Now().UTC().UnixNano())
-		return true
+	return infos
+}
+
+// Healthy checks whether at least one endpoint is connected, is not lagging
+// and its circuit breaker is closed.
+func (p *Pool) Healthy() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for i := range p.endpoints {
+		if p.isHealthy(i) {
+			return true
+		}
 	}
 
 	return false
 }
 
-// nextConnection returns healthy connection,
-// the second resp value is true if current connection was updated.
-// Returns error if there are no healthy connections.
-func (p *Pool) nextConnection() (*rpcclient.Client, bool, error) {
-	if p.isCurrentHealthy() {
-		return p.conn(), false, nil
-	}
+// isHealthy checks whether the endpoint with the given index is connected, is
+// not lagging and its circuit breaker is closed. Must be called with p.mu held.
+func (p *Pool) isHealthy(index int) bool {
+	ep := p.endpoints[index]
+	return ep.client != nil && ep.height != 0 && !p.isLagging(ep) && !ep.breakerOpen()
+}
+
+// maxHeight returns the highest known block count among all endpoints. Must
+// be called with p.mu held.
+func (p *Pool) maxHeight() uint32 {
+	var res uint32
 
-	if err := p.establishNewConnection(); err != nil {
-		return nil, false, err
+	for _, ep := range p.endpoints {
+		res = max(res, ep.height)
 	}
 
-	return p.conn(), true, nil
+	return res
 }
 
-// nextInvoker returns invoker wrapper on healthy connection.
-// Returns error if there are no healthy connections.
-func (p *Pool) nextInvoker() (*invoker.Invoker, error) {
-	if p.isCurrentHealthy() {
-		return p.invokerConn(), nil
+// isLagging checks whether the endpoint is too far behind the most up-to-date
+// one. Must be called with p.mu held.
+func (p *Pool) isLagging(ep *endpoint) bool {
+	return p.maxHeightLag > 0 && ep.height+p.maxHeightLag < p.maxHeight()
+}
+
+// updateLag exports the lag of the current endpoint. Must be called with p.mu
+// held.
+func (p *Pool) updateLag() {
+	if ep := p.endpoints[p.current]; ep.height != 0 {
+		p.metrics.currentLag.WithLabelValues(ep.Address).Set(float64(p.maxHeight() - ep.height))
 	}
+}
 
-	if err := p.establishNewConnection(); err != nil {
-		return nil, err
+// connect creates a client for the given endpoint and updates its metrics.
+func (p *Pool) connect(ctx context.Context, ep *endpoint) (*client, error) {
+	var start = time.Now()
+
+	cl, err := neoGoClient(ctx, ep.dialAddress(), p.opts)
+	p.metrics.dialDuration.WithLabelValues(ep.Address).Observe(time.Since(start).Seconds())
+	p.metrics.setEndpointUp(ep.Address, err == nil)
+
+	if err != nil {
+		ep.height = 0
 	}
 
-	return p.invokerConn(), nil
+	return cl, err
 }
 
-// GetIteratorInvoker returns invoker. It should be used with the contract iterator.
-// We must iterate with the specific node that handled reader request.
-func (p *Pool) GetIteratorInvoker() (*invoker.Invoker, error) {
-	return p.nextInvoker()
+// setCurrent makes the endpoint with the given index current. Must be called
+// with p.mu held.
+func (p *Pool) setCurrent(index int) {
+	if index != p.current {
+		p.metrics.currentLag.DeleteLabelValues(p.endpoints[p.current].Address)
+	}
+
+	p.metrics.endpointActive.WithLabelValues(p.endpoints[p.current].Address).Set(0)
+	p.metrics.endpointActive.WithLabelValues(p.endpoints[index].Address).Set(1)
+
+	p.current = index
 }
 
-// GetContractStateByID queries contract information, according to the contract ID.
-func (p *Pool) GetContractStateByID(id int32) (*state.Contract, error) {
-	conn, _, err := p.nextConnection()
+func (p *Pool) isCurrentHealthy() bool {
+	ep, conn := p.currentConn()
+	if conn == nil {
+		return false
+	}
+
+	p.mu.RLock()
+	open := ep.breakerOpen()
+	p.mu.RUnlock()
+
+	if open {
+		return false
+	}
+
+	if (time.Now().UTC().UnixNano() - atomic.LoadInt64(&p.lastHealthyTimestamp)) < p.recheckInterval.Nanoseconds() {
+		return true
+	}
+
+	height, latency, err := checkHeight(conn)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ep.update(height, latency, err)
+
 	if err != nil {
-		return nil, err
+		p.metrics.healthCheckFailures.WithLabelValues(ep.Address).Inc()
+		p.metrics.setEndpointUp(ep.Address, false)
+
+		return false
+	}
+
+	if ep == p.endpoints[p.current] {
+		p.updateLag()
+	}
+
+	if p.isLagging(ep) {
+		p.metrics.healthCheckFailures.WithLabelValues(ep.Address).Inc()
+		log.Printf("Neo node %s is %d blocks behind", ep.Address, p.maxHeight()-height)
+
+		return false
+	}
+
+	atomic.StoreInt64(&p.lastHealthyTimestamp, time.Now().UTC().UnixNano())
+
+	return true
+}
+
+// nextConnection returns healthy connection and its endpoint.
+// Returns error if there are no healthy connections.
+func (p *Pool) nextConnection() (*endpoint, *rpcclient.Client, error) {
+	if !p.isCurrentHealthy() {
+		if err := p.establishNewConnection(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ep, conn := p.currentConn()
+	if conn == nil {
+		return nil, nil, fmt.Errorf("no healthy client")
 	}
 
-	return conn.GetContractStateByID(id)
+	return ep, conn, nil
+}
+
+// GetContractStateByID queries contract information, according to the contract ID.
+func (p *Pool) GetContractStateByID(ctx context.Context, id int32) (*state.Contract, error) {
+	return cached(ctx, p, cacheKey("getcontractstate", id), func(conn *rpcclient.Client) (*state.Contract, error) {
+		return conn.GetContractStateByID(id)
+	})
 }
 
 // Call returns the results after calling the smart contract scripthash
 // with the given operation and parameters.
 // NOTE: this is test invoke and will not affect the blockchain.
-func (p *Pool) Call(contract util.Uint160, operation string, params ...any) (*result.Invoke, error) {
-	conn, err := p.nextInvoker()
-	if err != nil {
-		return nil, err
-	}
+func (p *Pool) Call(ctx context.Context, contract util.Uint160, operation string, params ...any) (*result.Invoke, error) {
+	key := cacheKey("invokefunction", append([]any{contract, operation}, params...)...)
 
-	return conn.Call(contract, operation, params...)
+	return cached(ctx, p, key, func(conn *rpcclient.Client) (*result.Invoke, error) {
+		return invoker.New(conn, nil).Call(contract, operation, params...)
+	})
 }
 
 // CallAndExpandIterator creates a script containing a call of the specified method
 // of a contract with given parameters (similar to how Call operates). But then this
 // script contains additional code that expects that the result of the first call is
 // an iterator.
-func (p *Pool) CallAndExpandIterator(contract util.Uint160, method string, maxItems int, params ...any) (*result.Invoke, error) {
-	conn, err := p.nextInvoker()
-	if err != nil {
-		return nil, err
-	}
+func (p *Pool) CallAndExpandIterator(ctx context.Context, contract util.Uint160, method string, maxItems int, params ...any) (*result.Invoke, error) {
+	return do(ctx, p, func(conn *rpcclient.Client) (*result.Invoke, error) {
+		return invoker.New(conn, nil).CallAndExpandIterator(contract, method, maxItems, params...)
+	})
+}
 
-	return conn.CallAndExpandIterator(contract, method, maxItems, params...)
+// Run executes the given read-only invocation script.
+func (p *Pool) Run(ctx context.Context, script []byte) (*result.Invoke, error) {
+	return cached(ctx, p, cacheKey("invokescript", script), func(conn *rpcclient.Client) (*result.Invoke, error) {
+		return invoker.New(conn, nil).Run(script)
+	})
 }
 
 // TerminateSession closes the given session, returning an error if anything
 // goes wrong. It's not strictly required to close the session (it'll expire on
 // the server anyway), but it helps to release server resources earlier.
-func (p *Pool) TerminateSession(sessionID uuid.UUID) error {
-	_, err := p.conn().TerminateSession(sessionID)
+func (p *Pool) TerminateSession(ctx context.Context, sessionID uuid.UUID) error {
+	_, err := do(ctx, p, func(conn *rpcclient.Client) (bool, error) {
+		return conn.TerminateSession(sessionID)
+	})
 	return err
 }
 
 // TraverseIterator allows to retrieve the next batch of items from the given
-// iterator in the given session (previously returned from Call).
-func (p *Pool) TraverseIterator(sessionID uuid.UUID, iterator *result.Iterator, num int) ([]stackitem.Item, error) {
-	inv := invoker.New(p.conn(), nil)
-	return inv.TraverseIterator(sessionID, iterator, num)
+// iterator in the given session (previously returned from Call). Session is
+// lost if the pool switches to another node after the Call, use [Pool.Iterate]
+// to read the whole iterator safely.
+func (p *Pool) TraverseIterator(ctx context.Context, sessionID uuid.UUID, iterator *result.Iterator, num int) ([]stackitem.Item, error) {
+	return do(ctx, p, func(conn *rpcclient.Client) ([]stackitem.Item, error) {
+		return invoker.New(conn, nil).TraverseIterator(sessionID, iterator, num)
+	})
 }
 
 // GetBlockCount returns the number of blocks in the main chain.
-func (p *Pool) GetBlockCount() (uint32, error) {
-	conn, _, err := p.nextConnection()
-	if err != nil {
-		return 0, err
-	}
+func (p *Pool) GetBlockCount(ctx context.Context) (uint32, error) {
+	return do(ctx, p, func(conn *rpcclient.Client) (uint32, error) {
+		return conn.GetBlockCount()
+	})
+}
+
+// GetNetwork returns the magic of the network RPC nodes belong to.
+func (p *Pool) GetNetwork(ctx context.Context) (netmode.Magic, error) {
+	return do(ctx, p, func(conn *rpcclient.Client) (netmode.Magic, error) {
+		v, err := conn.GetVersion()
+		if err != nil {
+			return 0, err
+		}
 
-	return conn.GetBlockCount()
+		return v.Protocol.Network, nil
+	})
 }
 
 // GetDesignatedByRole invokes `getDesignatedByRole` method on a native RoleManagement contract.
-func (p *Pool) GetDesignatedByRole(role noderoles.Role, height uint32) (keys.PublicKeys, error) {
-	invokerConn, err := p.nextInvoker()
-	if err != nil {
-		return nil, err
-	}
-
-	return rolemgmt.NewReader(invokerConn).GetDesignatedByRole(role, height)
+// The result is unwrapped after the call, so FAULT invocations are neither
+// retried nor reported to the endpoint circuit breaker.
+func (p *Pool) GetDesignatedByRole(ctx context.Context, role noderoles.Role, height uint32) (keys.PublicKeys, error) {
+	return rolemgmt.NewReader(p.Invoker(ctx)).GetDesignatedByRole(role, height)
 }
 
 // GetCommittee returns the current public keys of NEO nodes in committee.
-func (p *Pool) GetCommittee() (keys.PublicKeys, error) {
-	conn, _, err := p.nextConnection()
-	if err != nil {
-		return nil, err
-	}
-
-	return conn.GetCommittee()
+func (p *Pool) GetCommittee(ctx context.Context) (keys.PublicKeys, error) {
+	return cached(ctx, p, cacheKey("getcommittee"), func(conn *rpcclient.Client) (keys.PublicKeys, error) {
+		return conn.GetCommittee()
+	})
 }
 
-func (p *Pool) conn() *rpcclient.Client {
+func (p *Pool) currentConn() (*endpoint, *rpcclient.Client) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
-	return p.clients[p.current]
-}
 
-func (p *Pool) invokerConn() *invoker.Invoker {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	return invoker.New(p.clients[p.current], nil)
+	ep := p.endpoints[p.current]
+	if ep.client != nil {
+		return ep, ep.client.Client
+	}
+
+	return ep, nil
 }
 
+// establishNewConnection switches the pool to the first healthy endpoint in
+// the order defined by the pool strategy. Endpoints are redialed only if their
+// connection failed, lagging ones keep the connection and the last known
+// height.
 func (p *Pool) establishNewConnection() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	var err error
-
-	for i := p.next; i < p.next+len(p.endpoints); i++ {
-		index := i % len(p.endpoints)
-		if p.clients[p.current], err = neoGoClient(p.ctx, p.endpoints[index], p.opts); err == nil {
-			p.current = index % len(p.endpoints)
-			p.next = (index + 1) % len(p.endpoints)
-			return nil
+
+	for _, index := range p.strategy.Order(p.current, p.endpointInfos()) {
+		var (
+			ep  = p.endpoints[index]
+			err error
+		)
+
+		if ep.breakerOpen() {
+			continue
+		}
+
+		if ep.client != nil && index != p.current {
+			err = ep.check()
+			if err != nil {
+				p.metrics.healthCheckFailures.WithLabelValues(ep.Address).Inc()
+			}
+		}
+
+		// Zero height of the current endpoint means it has just failed the
+		// check.
+		if ep.client == nil || index == p.current && ep.height == 0 || err != nil {
+			if ep.client != nil {
+				ep.client.close()
+				ep.client = nil
+			}
+
+			p.metrics.reconnects.WithLabelValues(ep.Address).Inc()
+			ep.client, err = p.connect(p.ctx, ep)
+			if err != nil {
+				continue
+			}
+
+			if err = ep.check(); err != nil {
+				continue
+			}
+		}
+
+		if p.isLagging(ep) {
+			continue
 		}
+
+		p.setCurrent(index)
+		p.updateLag()
+
+		return nil
 	}
 
 	return fmt.Errorf("no healthy client")
 }
 
-func neoGoClient(ctx context.Context, endpoint string, opts rpcclient.Options) (*rpcclient.Client, error) {
-	cli, err := rpcclient.New(ctx, endpoint, opts)
-	if err != nil {
-		return nil, fmt.Errorf("create Neo RPC client: %w", err)
+// client is a connection to the single Neo RPC node. ws is set for WebSocket
+// endpoints only and allows to subscribe to chain events.
+type client struct {
+	*rpcclient.Client
+	ws *rpcclient.WSClient
+}
+
+func (c *client) close() {
+	if c.ws != nil {
+		c.ws.Close()
+		return
 	}
 
-	err = cli.Init()
+	c.Client.Close()
+}
+
+func neoGoClient(ctx context.Context, endpoint string, opts rpcclient.Options) (*client, error) {
+	var cl = new(client)
+
+	if isWebSocket(endpoint) {
+		ws, err := rpcclient.NewWS(ctx, endpoint, rpcclient.WSOptions{
+			Options:                        opts,
+			CloseNotificationChannelIfFull: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create Neo WebSocket RPC client: %w", err)
+		}
+
+		cl.Client, cl.ws = &ws.Client, ws
+	} else {
+		cli, err := rpcclient.New(ctx, endpoint, opts)
+		if err != nil {
+			return nil, fmt.Errorf("create Neo RPC client: %w", err)
+		}
+
+		cl.Client = cli
+	}
+
+	err := cl.Init()
 	if err != nil {
+		cl.close()
 		return nil, fmt.Errorf("init Neo RPC client: %w", err)
 	}
 
-	return cli, nil
+	return cl, nil
+}
+
+func isWebSocket(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "ws" || u.Scheme == "wss"
 }
 
 // ResolveContract helps to take contract address by contract name. Name list can be taken from contract wrappers,
 // for instance [rpcnns.NameNetmap].
-func (p *Pool) ResolveContract(contractName string) (util.Uint160, error) {
-	nnsHash, err := rpcnns.InferHash(p)
+func (p *Pool) ResolveContract(ctx context.Context, contractName string) (util.Uint160, error) {
+	var inv = p.Invoker(ctx)
+
+	nnsHash, err := rpcnns.InferHash(inv)
 	if err != nil {
 		return util.Uint160{}, fmt.Errorf("GetContractStateByID: %w", err)
 	}
 
-	nnsReader := rpcnns.NewReader(p, nnsHash)
+	nnsReader := rpcnns.NewReader(inv, nnsHash)
 	addr, err := nnsReader.ResolveFSContract(contractName)
 	if err != nil {
 		return util.Uint160{}, fmt.Errorf("ResolveFSContract [%s]: %w", contractName, err)
@@ -324,36 +691,37 @@ func (p *Pool) ResolveContract(contractName string) (util.Uint160, error) {
 	return addr, nil
 }
 
-func (p *Pool) FetchHeight() []monitor.HeightData {
+func (p *Pool) FetchHeight(ctx context.Context) []monitor.HeightData {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
 	var (
 		heights    []monitor.HeightData
 		wg         sync.WaitGroup
-		heightChan = make(chan monitor.HeightData, len(p.clients))
+		heightChan = make(chan monitor.HeightData, len(p.endpoints))
 	)
 
-	for _, cl := range p.clients {
+	for _, ep := range p.endpoints {
+		cl := ep.client
 		if cl == nil {
 			continue
 		}
 		wg.Add(1)
 
-		go func(cl *rpcclient.Client) {
+		go func(cl *client, address string) {
 			defer wg.Done()
 
-			stHeight, err := cl.GetStateHeight()
+			stHeight, err := call(ctx, p, cl.GetStateHeight)
 			if err != nil {
-				log.Printf("read state height of Neo node %s: %v", cl.Endpoint(), err)
+				log.Printf("read state height of Neo node %s: %v", address, err)
 				return
 			}
 
 			heightChan <- monitor.HeightData{
-				Host:  cl.Endpoint(),
+				Host:  address,
 				Value: stHeight.Local,
 			}
-		}(cl)
+		}(cl, ep.Address)
 	}
 
 	wg.Wait()
@@ -366,36 +734,39 @@ func (p *Pool) FetchHeight() []monitor.HeightData {
 	return heights
 }
 
-func (p *Pool) FetchState(height uint32) []monitor.StateData {
+func (p *Pool) FetchState(ctx context.Context, height uint32) []monitor.StateData {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
 	var (
 		states    []monitor.StateData
 		wg        sync.WaitGroup
-		stateChan = make(chan monitor.StateData, len(p.clients))
+		stateChan = make(chan monitor.StateData, len(p.endpoints))
 	)
 
-	for _, cl := range p.clients {
+	for _, ep := range p.endpoints {
+		cl := ep.client
 		if cl == nil {
 			continue
 		}
 		wg.Add(1)
 
-		go func(cl *rpcclient.Client) {
+		go func(cl *client, address string) {
 			defer wg.Done()
 
-			stHeight, err := cl.GetStateRootByHeight(height)
+			stHeight, err := call(ctx, p, func() (*state.MPTRoot, error) {
+				return cl.GetStateRootByHeight(height)
+			})
 			if err != nil {
-				log.Printf("read state root at height #%d from Neo node %s: %v", height, cl.Endpoint(), err)
+				log.Printf("read state root at height #%d from Neo node %s: %v", height, address, err)
 				return
 			}
 
 			stateChan <- monitor.StateData{
-				Host:  cl.Endpoint(),
+				Host:  address,
 				Value: stHeight.Hash().String(),
 			}
-		}(cl)
+		}(cl, ep.Address)
 	}
 
 	wg.Wait()
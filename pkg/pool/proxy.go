@@ -0,0 +1,137 @@
+package pool
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// proxySecretSize is the size of the random secret authorizing requests to
+// the endpoint proxy.
+const proxySecretSize = 32
+
+// endpointProxy forwards requests from the loopback address to the endpoint
+// applying its TLS, header and proxy settings. RPC client doesn't allow to
+// configure its transport, so it's connected to the proxy instead. The proxy
+// is reachable by other local users, so only requests with the random secret
+// path prefix known to the pool are forwarded, the others can't use the
+// endpoint credentials.
+type endpointProxy struct {
+	server *http.Server
+	// address is the endpoint address to connect RPC client to.
+	address string
+	// prefix is the secret path prefix of the proxied requests.
+	prefix string
+}
+
+// needsProxy checks whether the endpoint requires transport settings RPC
+// client doesn't support.
+func needsProxy(ep Endpoint) bool {
+	return ep.TLS != nil || len(ep.Header) != 0 || ep.Proxy != nil
+}
+
+func newEndpointProxy(ep Endpoint) (*endpointProxy, error) {
+	target, err := url.Parse(ep.Address)
+	if err != nil {
+		return nil, fmt.Errorf("parse endpoint address: %w", err)
+	}
+
+	local := *target
+
+	switch target.Scheme {
+	case "http", "https":
+		local.Scheme = "http"
+	case "ws":
+		local.Scheme, target.Scheme = "ws", "http"
+	case "wss":
+		local.Scheme, target.Scheme = "ws", "https"
+	default:
+		return nil, fmt.Errorf("unsupported endpoint scheme %q", target.Scheme)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = ep.TLS
+	if ep.Proxy != nil {
+		transport.Proxy = http.ProxyURL(ep.Proxy)
+	}
+
+	secret := make([]byte, proxySecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("generate proxy secret: %w", err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	var (
+		prefix = "/" + hex.EncodeToString(secret)
+		proxy  = &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				r.SetURL(target)
+				for k, v := range ep.Header {
+					r.Out.Header[k] = v
+				}
+			},
+			Transport: transport,
+		}
+	)
+
+	local.Host = l.Addr().String()
+	local.Path, local.RawPath = prefix+target.Path, ""
+	target.Path, target.RawPath = "", ""
+
+	var p = &endpointProxy{
+		address: local.String(),
+		prefix:  prefix,
+	}
+
+	p.server = &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, ok := p.authorize(r.URL.Path)
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+
+			r.URL.Path, r.URL.RawPath = path, ""
+			proxy.ServeHTTP(w, r)
+		}),
+	}
+
+	go func() {
+		err := p.server.Serve(l)
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("proxy to Neo node %s stopped: %v", ep.Address, err)
+		}
+	}()
+
+	return p, nil
+}
+
+// authorize checks the secret prefix of the request path and returns the
+// path without it.
+func (p *endpointProxy) authorize(path string) (string, bool) {
+	if len(path) < len(p.prefix) || subtle.ConstantTimeCompare([]byte(path[:len(p.prefix)]), []byte(p.prefix)) != 1 {
+		return "", false
+	}
+
+	path = path[len(p.prefix):]
+	if path != "" && path[0] != '/' {
+		return "", false
+	}
+
+	return path, true
+}
+
+func (p *endpointProxy) close() {
+	_ = p.server.Close()
+}
@@ -0,0 +1,57 @@
+package pool
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointProxy(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	t.Cleanup(srv.Close)
+
+	p, err := newEndpointProxy(Endpoint{
+		Address: srv.URL + "/rpc",
+		TLS:     &tls.Config{RootCAs: srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs},
+		Header:  http.Header{"Authorization": {"Bearer token"}},
+	})
+	require.NoError(t, err)
+	t.Cleanup(p.close)
+
+	require.Regexp(t, `^http://127\.0\.0\.1:\d+/[0-9a-f]{64}/rpc$`, p.address)
+
+	resp, err := http.Post(p.address, "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "/rpc", string(body))
+
+	// Requests without the secret don't reach the endpoint.
+	u, err := url.Parse(p.address)
+	require.NoError(t, err)
+
+	for _, path := range []string{"/rpc", "/", "/" + strings.Repeat("0", 64) + "/rpc", p.prefix + "rpc"} {
+		u.Path = path
+
+		resp, err := http.Post(u.String(), "application/json", nil)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+}
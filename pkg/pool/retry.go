@@ -0,0 +1,126 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+)
+
+type (
+	// RetryPolicy defines how requests failed because of transport errors are
+	// repeated. Requests rejected by RPC node and FAULT invocations are never
+	// repeated.
+	RetryPolicy struct {
+		// Attempts is the maximum number of request attempts, zero or one
+		// disables retries.
+		Attempts int
+		// MinBackoff is the delay before the first retry, it's doubled for
+		// every next one.
+		MinBackoff time.Duration
+		// MaxBackoff limits the delay between retries.
+		MaxBackoff time.Duration
+	}
+
+	// BreakerPolicy defines when the endpoint circuit breaker opens. Endpoint
+	// with open breaker is considered unhealthy until the cooldown ends, then
+	// the next failure opens the breaker again while a successful request
+	// closes it.
+	BreakerPolicy struct {
+		// Threshold is the number of consecutive failed requests opening the
+		// breaker, zero disables the breaker.
+		Threshold int
+		// Cooldown is the time the breaker stays open.
+		Cooldown time.Duration
+	}
+)
+
+// backoff returns the delay before the retry following the given attempt
+// (starting from zero) with random jitter of up to a half of it.
+func (r RetryPolicy) backoff(attempt int) time.Duration {
+	d := r.MinBackoff << attempt
+	if d <= 0 || (r.MaxBackoff > 0 && d > r.MaxBackoff) {
+		d = r.MaxBackoff
+	}
+
+	if d <= 0 {
+		return 0
+	}
+
+	return d/2 + rand.N(d/2+1)
+}
+
+// do runs f on the current endpoint connection limited by ctx and the pool
+// call timeout. Transport errors are reported to the endpoint circuit breaker
+// and the request is repeated according to the pool retry policy. f must only
+// make the RPC request, its result (e.g. FAULT invocation or unexpected stack
+// items) is to be unwrapped by the caller, otherwise such errors are taken for
+// transport ones.
+func do[T any](ctx context.Context, p *Pool, f func(*rpcclient.Client) (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := call(ctx, p, func() (T, error) {
+			ep, conn, err := p.nextConnection()
+			if err != nil {
+				var zero T
+				return zero, err
+			}
+
+			res, err := f(conn)
+			p.report(ep, err)
+
+			return res, err
+		})
+
+		if err == nil || !isRetryable(err) || attempt+1 >= p.retry.Attempts || ctx.Err() != nil {
+			return res, err
+		}
+
+		p.metrics.retries.Inc()
+
+		select {
+		case <-time.After(p.retry.backoff(attempt)):
+		case <-ctx.Done():
+			return res, err
+		}
+	}
+}
+
+// isRetryable checks whether the request failed because of transport error
+// and can be repeated.
+func isRetryable(err error) bool {
+	var rpcErr *neorpc.Error
+
+	return err != nil && !errors.As(err, &rpcErr) && !errors.Is(err, context.Canceled)
+}
+
+// report records the result of the request to the endpoint for its circuit
+// breaker.
+func (p *Pool) report(ep *endpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !isRetryable(err) {
+		if ep.failures != 0 {
+			ep.failures = 0
+			ep.openUntil = time.Time{}
+			p.metrics.breakerOpen.WithLabelValues(ep.Address).Set(0)
+		}
+
+		return
+	}
+
+	p.metrics.requestFailures.WithLabelValues(ep.Address).Inc()
+	ep.failures++
+
+	// Check the endpoint before the next request.
+	atomic.StoreInt64(&p.lastHealthyTimestamp, 0)
+
+	if p.breaker.Threshold > 0 && ep.failures >= p.breaker.Threshold {
+		ep.openUntil = time.Now().Add(p.breaker.Cooldown)
+		p.metrics.breakerOpen.WithLabelValues(ep.Address).Set(1)
+	}
+}
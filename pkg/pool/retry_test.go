@@ -0,0 +1,163 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/pkg/rpctest"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	var r = RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt, expected := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		d := r.backoff(attempt)
+		require.GreaterOrEqual(t, d, expected/2)
+		require.LessOrEqual(t, d, expected)
+	}
+
+	require.LessOrEqual(t, r.backoff(100), time.Second)
+	require.Zero(t, RetryPolicy{}.backoff(1))
+}
+
+func TestIsRetryable(t *testing.T) {
+	require.False(t, isRetryable(nil))
+	require.False(t, isRetryable(fmt.Errorf("call: %w", neorpc.ErrUnknownContract)))
+	require.False(t, isRetryable(context.Canceled))
+	require.True(t, isRetryable(context.DeadlineExceeded))
+	require.True(t, isRetryable(errors.New("connection refused")))
+}
+
+func TestDo(t *testing.T) {
+	var ctx = t.Context()
+
+	// requireFailures checks the number of retries and failed requests
+	// reported to the breaker of the node.
+	requireFailures := func(t *testing.T, p *Pool, n *testNode, retries, failures int) {
+		require.EqualValues(t, retries, testutil.ToFloat64(p.metrics.retries))
+		require.EqualValues(t, failures, testutil.ToFloat64(p.metrics.requestFailures.WithLabelValues(n.address)))
+	}
+
+	t.Run("attempts", func(t *testing.T) {
+		var (
+			a = newTestNode(t, 100, nil)
+			p = newTestPool(t, PrmPool{
+				Retry: RetryPolicy{Attempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+			}, a)
+		)
+
+		a.faults.SetFor("invokefunction", rpctest.Fault{Drop: true})
+
+		_, err := p.Call(ctx, util.Uint160{}, "test")
+		require.Error(t, err)
+		requireFailures(t, p, a, 2, 3)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		var (
+			a = newTestNode(t, 100, nil)
+			p = newTestPool(t, PrmPool{
+				Retry:   RetryPolicy{Attempts: 3},
+				Breaker: BreakerPolicy{Threshold: 1, Cooldown: time.Hour},
+			}, a)
+		)
+
+		a.faults.SetFor("invokefunction", rpctest.Fault{Error: testErr})
+
+		_, err := p.Call(ctx, util.Uint160{}, "test")
+		require.ErrorIs(t, err, testErr)
+		requireFailures(t, p, a, 0, 0)
+		require.True(t, p.Healthy())
+	})
+
+	t.Run("fault", func(t *testing.T) {
+		var (
+			a = newTestNode(t, 100, nil)
+			p = newTestPool(t, PrmPool{
+				Retry:   RetryPolicy{Attempts: 3},
+				Breaker: BreakerPolicy{Threshold: 1, Cooldown: time.Hour},
+			}, a)
+		)
+
+		// Unexpected stack items are not transport errors either.
+		_, err := p.GetDesignatedByRole(ctx, noderoles.NeoFSAlphabet, 100)
+		require.Error(t, err)
+
+		a.SetFault("test exception")
+
+		_, err = p.GetDesignatedByRole(ctx, noderoles.NeoFSAlphabet, 100)
+		require.ErrorContains(t, err, "test exception")
+
+		require.Equal(t, 2, a.Calls("invokefunction"))
+		requireFailures(t, p, a, 0, 0)
+		require.True(t, p.Healthy())
+	})
+}
+
+func TestBreaker(t *testing.T) {
+	const cooldown = 200 * time.Millisecond
+
+	var (
+		ctx = t.Context()
+		a   = newTestNode(t, 100, nil)
+		b   = newTestNode(t, 100, nil)
+		p   = newTestPool(t, PrmPool{
+			Breaker: BreakerPolicy{Threshold: 2, Cooldown: cooldown},
+		}, a, b)
+		breakerOpen = func() float64 {
+			return testutil.ToFloat64(p.metrics.breakerOpen.WithLabelValues(a.address))
+		}
+	)
+
+	a.faults.SetFor("invokefunction", rpctest.Fault{Drop: true})
+
+	// Failures below the threshold keep the endpoint.
+	_, err := p.Call(ctx, util.Uint160{}, "test")
+	require.Error(t, err)
+	requireCurrent(t, p, a)
+	require.Zero(t, breakerOpen())
+
+	_, err = p.Call(ctx, util.Uint160{}, "test")
+	require.Error(t, err)
+	require.EqualValues(t, 1, breakerOpen())
+
+	// Endpoint with open breaker is skipped even if it recovers.
+	a.faults.Reset()
+
+	_, err = p.Call(ctx, util.Uint160{}, "test")
+	require.NoError(t, err)
+	requireCurrent(t, p, b)
+
+	p.recheck(ctx)
+	requireCurrent(t, p, b)
+
+	// The pool returns to the endpoint after the cooldown, the successful
+	// request closes the breaker.
+	time.Sleep(cooldown)
+
+	p.recheck(ctx)
+	requireCurrent(t, p, a)
+	require.Zero(t, breakerOpen())
+
+	_, err = p.Call(ctx, util.Uint160{}, "test")
+	require.NoError(t, err)
+
+	p.mu.RLock()
+	require.Zero(t, p.endpoints[0].failures)
+	p.mu.RUnlock()
+}
@@ -0,0 +1,143 @@
+package pool
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Strategy defines the order of endpoint selection.
+type Strategy interface {
+	// Order returns endpoint indexes in the order of preference, current is
+	// the index of the endpoint in use. Pool switches to the first healthy
+	// endpoint from the list on every health recheck and when the current
+	// endpoint fails.
+	Order(current int, endpoints []EndpointInfo) []int
+}
+
+// Names of strategies supported by [NewStrategy].
+const (
+	StrategyPriority   = "priority"
+	StrategyLatency    = "latency"
+	StrategyRoundRobin = "round_robin"
+)
+
+// latencyTolerance is the relative latency difference [NewLatencyStrategy]
+// ignores to avoid switching between endpoints with similar response times.
+const latencyTolerance = 0.2
+
+type (
+	priorityStrategy   struct{}
+	latencyStrategy    struct{}
+	roundRobinStrategy struct{}
+)
+
+// NewStrategy returns strategy by its name, empty name means
+// [StrategyPriority].
+func NewStrategy(name string) (Strategy, error) {
+	switch name {
+	case "", StrategyPriority:
+		return NewPriorityStrategy(), nil
+	case StrategyLatency:
+		return NewLatencyStrategy(), nil
+	case StrategyRoundRobin:
+		return NewRoundRobinStrategy(), nil
+	default:
+		return nil, fmt.Errorf("unknown endpoint selection strategy %q", name)
+	}
+}
+
+// NewPriorityStrategy returns strategy preferring endpoints with lower
+// [Endpoint.Priority] values. Endpoints of the next priority group are used
+// only if all endpoints of the previous groups are unavailable, pool returns
+// to the preferred group as soon as any of its endpoints recovers. Within the
+// group, the current endpoint is kept while it's healthy, otherwise the most
+// up-to-date one is preferred.
+func NewPriorityStrategy() Strategy {
+	return priorityStrategy{}
+}
+
+// NewLatencyStrategy returns strategy preferring endpoints with the lowest
+// health check response time.
+func NewLatencyStrategy() Strategy {
+	return latencyStrategy{}
+}
+
+// NewRoundRobinStrategy returns strategy switching to the next healthy
+// endpoint on every health recheck to spread the load between endpoints.
+func NewRoundRobinStrategy() Strategy {
+	return roundRobinStrategy{}
+}
+
+func (priorityStrategy) Order(current int, endpoints []EndpointInfo) []int {
+	order := nextOrder(current, len(endpoints))
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		if c := cmp.Compare(endpoints[a].Priority, endpoints[b].Priority); c != 0 {
+			return c
+		}
+
+		switch {
+		case a == current && endpoints[a].Healthy:
+			return -1
+		case b == current && endpoints[b].Healthy:
+			return 1
+		}
+
+		return cmp.Compare(endpoints[b].Height, endpoints[a].Height)
+	})
+
+	return order
+}
+
+func (latencyStrategy) Order(current int, endpoints []EndpointInfo) []int {
+	order := nextOrder(current, len(endpoints))
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(latencyOrMax(endpoints[a]), latencyOrMax(endpoints[b]))
+	})
+
+	if current >= len(endpoints) || !endpoints[current].Healthy {
+		return order
+	}
+
+	for _, index := range order {
+		if !endpoints[index].Healthy {
+			continue
+		}
+
+		fastest := float64(endpoints[index].Latency)
+		if float64(endpoints[current].Latency) <= fastest*(1+latencyTolerance) {
+			i := slices.Index(order, current)
+			order = slices.Insert(slices.Delete(order, i, i+1), 0, current)
+		}
+
+		break
+	}
+
+	return order
+}
+
+func (roundRobinStrategy) Order(current int, endpoints []EndpointInfo) []int {
+	return nextOrder(current, len(endpoints))
+}
+
+// nextOrder returns n indexes starting from the one following current, so
+// current is the last one.
+func nextOrder(current, n int) []int {
+	order := make([]int, 0, n)
+	for i := range n {
+		order = append(order, (current+1+i)%n)
+	}
+
+	return order
+}
+
+func latencyOrMax(info EndpointInfo) time.Duration {
+	if info.Latency == 0 {
+		return time.Duration(1<<63 - 1)
+	}
+
+	return info.Latency
+}
@@ -0,0 +1,79 @@
+package pool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityStrategy(t *testing.T) {
+	var s = NewPriorityStrategy()
+
+	for _, tc := range []struct {
+		name      string
+		current   int
+		endpoints []EndpointInfo
+		expected  []int
+	}{
+		{
+			name:    "keep healthy current",
+			current: 1,
+			endpoints: []EndpointInfo{
+				{Healthy: true, Height: 10},
+				{Healthy: true, Height: 9},
+				{Healthy: true, Height: 10},
+			},
+			expected: []int{1, 2, 0},
+		},
+		{
+			name:    "most up-to-date in group",
+			current: 0,
+			endpoints: []EndpointInfo{
+				{Height: 0},
+				{Healthy: true, Height: 9},
+				{Healthy: true, Height: 10},
+			},
+			expected: []int{2, 1, 0},
+		},
+		{
+			name:    "return to preferred group",
+			current: 2,
+			endpoints: []EndpointInfo{
+				{Healthy: true, Height: 5},
+				{Endpoint: Endpoint{Priority: 1}, Healthy: true, Height: 10},
+				{Endpoint: Endpoint{Priority: 1}, Healthy: true, Height: 10},
+			},
+			expected: []int{0, 2, 1},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, s.Order(tc.current, tc.endpoints))
+		})
+	}
+}
+
+func TestLatencyStrategy(t *testing.T) {
+	var s = NewLatencyStrategy()
+
+	endpoints := []EndpointInfo{
+		{Healthy: true, Latency: 110 * time.Millisecond},
+		{Healthy: true, Latency: 100 * time.Millisecond},
+		{Healthy: true},
+		{Healthy: true, Latency: 50 * time.Millisecond},
+	}
+
+	require.Equal(t, []int{3, 1, 0, 2}, s.Order(0, endpoints))
+
+	endpoints[3].Healthy = false
+	require.Equal(t, []int{0, 3, 1, 2}, s.Order(0, endpoints))
+}
+
+func TestRoundRobinStrategy(t *testing.T) {
+	var s = NewRoundRobinStrategy()
+
+	endpoints := make([]EndpointInfo, 3)
+
+	require.Equal(t, []int{1, 2, 0}, s.Order(0, endpoints))
+	require.Equal(t, []int{0, 1, 2}, s.Order(2, endpoints))
+}
@@ -0,0 +1,183 @@
+package pool
+
+import (
+	"log"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+)
+
+// headersBufferSize is the capacity of the channel receiving block headers
+// from the WebSocket client.
+const headersBufferSize = 16
+
+// subscription forwards new block notifications received from the WebSocket
+// endpoint to the subscriber.
+type subscription struct {
+	endpoint *endpoint
+	ws       *rpcclient.WSClient
+	id       string
+	// onBlock is called with the endpoint and the new block height.
+	onBlock func(*endpoint, uint32)
+	// wg tracks goroutines of the subscription, so that the pool is closed
+	// only after they stop using it.
+	wg *sync.WaitGroup
+
+	blocks   chan uint32
+	done     chan struct{}
+	finished chan struct{}
+	stopOnce sync.Once
+}
+
+// Blocks returns a channel receiving heights of new blocks if the current
+// endpoint is a WebSocket one and the subscription is active, nil otherwise.
+// Only the latest height is kept in the channel, so slow readers skip
+// intermediate blocks. The channel is closed when the subscription breaks,
+// Blocks must be called again to get a new one after that.
+func (p *Pool) Blocks() <-chan uint32 {
+	p.subMu.Lock()
+	defer p.subMu.Unlock()
+
+	if p.sub == nil || !p.sub.alive() {
+		return nil
+	}
+
+	return p.sub.blocks
+}
+
+// subscribed checks whether new block notifications of the endpoint are
+// received.
+func (p *Pool) subscribed(ep *endpoint) bool {
+	p.subMu.Lock()
+	defer p.subMu.Unlock()
+
+	return p.sub != nil && p.sub.alive() && p.sub.endpoint == ep
+}
+
+// resubscribe binds new block subscription to the current endpoint. It drops
+// broken subscriptions and the ones made via the endpoint that is not
+// current anymore.
+func (p *Pool) resubscribe() {
+	p.mu.RLock()
+	ep := p.endpoints[p.current]
+	cl := ep.client
+	p.mu.RUnlock()
+
+	p.subMu.Lock()
+	defer p.subMu.Unlock()
+
+	// Closed pool doesn't start goroutines anymore, Close may be waiting for
+	// them already.
+	if p.ctx.Err() != nil {
+		return
+	}
+
+	if p.sub != nil {
+		if p.sub.alive() && p.sub.endpoint == ep && cl != nil && p.sub.ws == cl.ws {
+			return
+		}
+
+		p.sub.stop()
+		p.sub = nil
+	}
+
+	if cl == nil || cl.ws == nil {
+		return
+	}
+
+	sub, err := subscribe(ep, cl.ws, p.observeBlock, &p.wg)
+	if err != nil {
+		log.Printf("subscribe to new blocks of Neo node %s: %v", ep.Address, err)
+		return
+	}
+
+	p.sub = sub
+}
+
+func subscribe(ep *endpoint, ws *rpcclient.WSClient, onBlock func(*endpoint, uint32), wg *sync.WaitGroup) (*subscription, error) {
+	headers := make(chan *block.Header, headersBufferSize)
+
+	id, err := ws.ReceiveHeadersOfAddedBlocks(nil, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &subscription{
+		endpoint: ep,
+		ws:       ws,
+		id:       id,
+		onBlock:  onBlock,
+		wg:       wg,
+		blocks:   make(chan uint32, 1),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+
+	wg.Go(func() { s.forward(headers) })
+
+	return s, nil
+}
+
+func (s *subscription) forward(headers <-chan *block.Header) {
+	// Subscription must be seen as finished by the moment subscriber
+	// observes closed blocks channel.
+	defer close(s.blocks)
+	defer close(s.finished)
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case h, ok := <-headers:
+			if !ok {
+				// Connection is lost, WSClient closes receivers in this case.
+				return
+			}
+
+			s.onBlock(s.endpoint, h.Index)
+
+			// Subscriber needs the chain tip only, replace stale height if
+			// it hasn't been read yet.
+			select {
+			case <-s.blocks:
+			default:
+			}
+
+			s.blocks <- h.Index
+		}
+	}
+}
+
+// observeBlock updates the height of the endpoint on new block notification.
+func (p *Pool) observeBlock(ep *endpoint, height uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Unhealthy endpoint is not updated until it passes the health check.
+	if ep.height != 0 {
+		// Block count is greater than the last block index by one.
+		ep.height = max(ep.height, height+1)
+	}
+}
+
+func (s *subscription) alive() bool {
+	select {
+	case <-s.finished:
+		return false
+	default:
+		return true
+	}
+}
+
+func (s *subscription) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+
+		// Notifications are not read anymore, but the channel is closed by
+		// the client if it overflows, so unsubscription never blocks.
+		s.wg.Go(func() {
+			_ = s.ws.Unsubscribe(s.id)
+		})
+	})
+}
@@ -0,0 +1,222 @@
+package pool
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-exporter/internal/chaintest"
+	"github.com/nspcc-dev/neo-exporter/pkg/monitor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// tcpProxy forwards TCP connections to the target address and breaks them on
+// request.
+type tcpProxy struct {
+	ln     net.Listener
+	target string
+
+	mu    sync.Mutex
+	conns []net.Conn
+}
+
+func newTCPProxy(t *testing.T, target string) *tcpProxy {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	p := &tcpProxy{ln: ln, target: target}
+	t.Cleanup(func() {
+		_ = ln.Close()
+		p.breakConns()
+	})
+
+	go p.serve()
+
+	return p
+}
+
+func (p *tcpProxy) serve() {
+	for {
+		in, err := p.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		out, err := net.Dial("tcp", p.target)
+		if err != nil {
+			_ = in.Close()
+			continue
+		}
+
+		p.mu.Lock()
+		p.conns = append(p.conns, in, out)
+		p.mu.Unlock()
+
+		go func() {
+			_, _ = io.Copy(out, in)
+			_ = out.Close()
+		}()
+		go func() {
+			_, _ = io.Copy(in, out)
+			_ = in.Close()
+		}()
+	}
+}
+
+// breakConns closes all connections made so far.
+func (p *tcpProxy) breakConns() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, c := range p.conns {
+		_ = c.Close()
+	}
+
+	p.conns = nil
+}
+
+// runsCollector reports every run to the channel.
+type runsCollector chan struct{}
+
+func (c runsCollector) Name() string {
+	return "test"
+}
+
+func (c runsCollector) Process(context.Context) (int, error) {
+	c <- struct{}{}
+	return 0, nil
+}
+
+func (c runsCollector) Metrics() []prometheus.Collector {
+	return nil
+}
+
+func requireBlock(t *testing.T, blocks <-chan uint32, height uint32) {
+	select {
+	case h, ok := <-blocks:
+		require.True(t, ok)
+		require.Equal(t, height, h)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no new block notification")
+	}
+}
+
+func requireClosed(t *testing.T, blocks <-chan uint32) {
+	for {
+		select {
+		case _, ok := <-blocks:
+			if !ok {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("blocks channel is not closed")
+		}
+	}
+}
+
+func requireRuns(t *testing.T, runs runsCollector, n int) {
+	for range n {
+		select {
+		case <-runs:
+		case <-time.After(5 * time.Second):
+			t.Fatal("collector is not run")
+		}
+	}
+}
+
+func TestSubscription(t *testing.T) {
+	var (
+		ctx   = t.Context()
+		chain = chaintest.New(t)
+		proxy = newTCPProxy(t, strings.TrimPrefix(chain.Address, "http://"))
+	)
+
+	p, err := NewPool(ctx, PrmPool{
+		Endpoints:       []Endpoint{{Address: "ws://" + proxy.ln.Addr().String() + "/ws"}},
+		DialTimeout:     time.Second,
+		RecheckInterval: time.Hour,
+	})
+	require.NoError(t, err)
+
+	// New blocks are forwarded to the subscriber.
+	blocks := p.Blocks()
+	require.NotNil(t, blocks)
+
+	chain.AddNewBlock(t)
+	requireBlock(t, blocks, chain.Chain.BlockHeight())
+
+	// Collectors run on new blocks only while notifications are available.
+	var (
+		runs      = make(runsCollector, 100)
+		scheduler = monitor.NewScheduler(zap.NewNop(), p, nil, []monitor.ScheduledCollector{{
+			Collector: runs,
+			Schedule:  monitor.Schedule{Interval: 10 * time.Millisecond, Blocks: 1},
+		}})
+		schedCtx, cancel = context.WithCancel(ctx)
+		done             = make(chan struct{})
+	)
+
+	go func() {
+		scheduler.Run(schedCtx)
+		close(done)
+	}()
+
+	requireRuns(t, runs, 1)
+
+	chain.AddNewBlock(t)
+	requireRuns(t, runs, 1)
+
+	// Broken connection closes the channel, no subscription is available
+	// until the pool reconnects, so collectors fall back to polling.
+	proxy.breakConns()
+	requireClosed(t, blocks)
+	require.Nil(t, p.Blocks())
+	requireRuns(t, runs, 3)
+
+	// The scheduler would read notifications of the new subscription.
+	cancel()
+	<-done
+
+	// The pool subscribes again after reconnection.
+	p.recheck(ctx)
+	p.resubscribe()
+
+	blocks = p.Blocks()
+	require.NotNil(t, blocks)
+
+	chain.AddNewBlock(t)
+	requireBlock(t, blocks, chain.Chain.BlockHeight())
+
+	// Notifications are not forwarded to the closed pool.
+	p.subMu.Lock()
+	sub := p.sub
+	p.subMu.Unlock()
+
+	require.NoError(t, p.Close(ctx))
+	require.False(t, sub.alive())
+	requireClosed(t, blocks)
+}
+
+func TestSubscriptionHTTP(t *testing.T) {
+	var (
+		ctx   = t.Context()
+		chain = chaintest.New(t)
+	)
+
+	p, err := NewPool(ctx, PrmPool{
+		Endpoints:       []Endpoint{{Address: chain.Address}},
+		DialTimeout:     time.Second,
+		RecheckInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, p.Close(context.Background())) })
+
+	// Block notifications are not available via HTTP, collectors poll.
+	require.Nil(t, p.Blocks())
+}
@@ -0,0 +1,138 @@
+package rpctest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+)
+
+// Fault describes misbehaviour injected by [FaultProxy]. Zero Fault passes
+// requests through unchanged.
+type Fault struct {
+	// Latency delays requests before any other fault is applied.
+	Latency time.Duration
+	// Drop closes the connection without response.
+	Drop bool
+	// Stall holds requests until the client gives up or faults are changed,
+	// empty response is returned then.
+	Stall bool
+	// Error is returned instead of the response.
+	Error *neorpc.Error
+	// Height is returned as the block count instead of the actual one if set.
+	Height uint32
+}
+
+// FaultProxy is the HTTP handler injecting faults into JSON-RPC requests
+// before passing them to the next handler.
+type FaultProxy struct {
+	next http.Handler
+
+	mu      sync.Mutex
+	fault   Fault
+	methods map[string]Fault
+	// release is closed when faults are changed to resume stalled requests.
+	release chan struct{}
+}
+
+// NewFaultProxy creates FaultProxy passing requests to next.
+func NewFaultProxy(next http.Handler) *FaultProxy {
+	return &FaultProxy{
+		next:    next,
+		methods: make(map[string]Fault),
+		release: make(chan struct{}),
+	}
+}
+
+// Set injects the fault into all requests except for the methods configured
+// via [FaultProxy.SetFor].
+func (p *FaultProxy) Set(f Fault) {
+	p.mu.Lock()
+	p.fault = f
+	p.resume()
+	p.mu.Unlock()
+}
+
+// SetFor injects the fault into requests of the given method.
+func (p *FaultProxy) SetFor(method string, f Fault) {
+	p.mu.Lock()
+	p.methods[method] = f
+	p.resume()
+	p.mu.Unlock()
+}
+
+// Reset removes all faults.
+func (p *FaultProxy) Reset() {
+	p.mu.Lock()
+	p.fault = Fault{}
+	clear(p.methods)
+	p.resume()
+	p.mu.Unlock()
+}
+
+// resume releases stalled requests. Must be called with p.mu held.
+func (p *FaultProxy) resume() {
+	close(p.release)
+	p.release = make(chan struct{})
+}
+
+// ServeHTTP implements [http.Handler].
+func (p *FaultProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	in, err := decodeRequest(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	f, ok := p.methods[in.Method]
+	if !ok {
+		f = p.fault
+	}
+	release := p.release
+	p.mu.Unlock()
+
+	if f.Latency > 0 {
+		select {
+		case <-time.After(f.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	switch {
+	case f.Stall:
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+
+		return
+	case f.Drop:
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			panic("rpctest: connection can't be dropped")
+		}
+
+		conn, _, err := hj.Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+	case f.Error != nil:
+		writeResponse(w, in.ID, nil, f.Error)
+	case f.Height != 0 && in.Method == "getblockcount":
+		writeResponse(w, in.ID, f.Height, nil)
+	default:
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		p.next.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,241 @@
+package rpctest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+)
+
+// Node is the stand-in RPC node. It reports the configured block height and
+// answers every contract call with a session iterator over the configured
+// items. With sessions disabled, contract calls return truncated iterators
+// and scripts return arrays of all configured items. Calls can be made to
+// FAULT via [Node.SetFault].
+type Node struct {
+	mu         sync.Mutex
+	height     uint32
+	items      []stackitem.Item
+	noSessions bool
+	fault      string
+	sessions   map[uuid.UUID]*nodeSession
+	calls      map[string]int
+}
+
+type nodeSession struct {
+	iterator uuid.UUID
+	items    []stackitem.Item
+}
+
+// NewNode creates Node with the given block count.
+func NewNode(height uint32) *Node {
+	return &Node{
+		height:   height,
+		sessions: make(map[uuid.UUID]*nodeSession),
+		calls:    make(map[string]int),
+	}
+}
+
+// SetHeight sets the block count returned by the node.
+func (n *Node) SetHeight(height uint32) {
+	n.mu.Lock()
+	n.height = height
+	n.mu.Unlock()
+}
+
+// SetItems sets items of iterators returned by contract calls.
+func (n *Node) SetItems(items []stackitem.Item) {
+	n.mu.Lock()
+	n.items = items
+	n.mu.Unlock()
+}
+
+// DisableSessions makes the node answer like the one with iterator sessions
+// disabled.
+func (n *Node) DisableSessions() {
+	n.mu.Lock()
+	n.noSessions = true
+	n.mu.Unlock()
+}
+
+// SetFault makes contract calls and scripts end in FAULT state with the given
+// exception, empty exception resets it.
+func (n *Node) SetFault(exception string) {
+	n.mu.Lock()
+	n.fault = exception
+	n.mu.Unlock()
+}
+
+// Sessions returns the number of iterator sessions that are not terminated.
+func (n *Node) Sessions() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return len(n.sessions)
+}
+
+// Calls returns the number of requests of the given method served by the node.
+func (n *Node) Calls(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.calls[method]
+}
+
+// ServeHTTP implements [http.Handler].
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	in, err := decodeRequest(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, rpcErr := n.handle(in)
+
+	writeResponse(w, in.ID, res, rpcErr)
+}
+
+func (n *Node) handle(req request) (any, *neorpc.Error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.calls[req.Method]++
+
+	switch req.Method {
+	case "getversion":
+		return result.Version{
+			UserAgent: "/rpctest/",
+			Protocol: result.Protocol{
+				Network:         netmode.UnitTestNet,
+				AddressVersion:  0x35,
+				ValidatorsCount: 1,
+			},
+		}, nil
+	case "getnativecontracts":
+		return []any{}, nil
+	case "getblockcount":
+		return n.height, nil
+	case "invokefunction", "invokescript":
+		if n.fault != "" {
+			return &result.Invoke{
+				State:          vmstate.Fault.String(),
+				FaultException: n.fault,
+			}, nil
+		}
+
+		if n.noSessions {
+			if req.Method == "invokescript" {
+				return &result.Invoke{
+					State: vmstate.Halt.String(),
+					Stack: []stackitem.Item{stackitem.NewArray(n.items)},
+				}, nil
+			}
+
+			return &result.Invoke{
+				State: vmstate.Halt.String(),
+				Stack: []stackitem.Item{stackitem.NewInterop(result.Iterator{Truncated: true})},
+			}, nil
+		}
+
+		var (
+			sid = uuid.New()
+			s   = &nodeSession{iterator: uuid.New(), items: n.items}
+		)
+
+		n.sessions[sid] = s
+
+		return &result.Invoke{
+			State:   vmstate.Halt.String(),
+			Stack:   []stackitem.Item{stackitem.NewInterop(result.Iterator{ID: &s.iterator})},
+			Session: sid,
+		}, nil
+	case "traverseiterator":
+		var (
+			params []any
+			sid    uuid.UUID
+			count  float64
+		)
+
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params) != 3 {
+			return nil, neorpc.NewInvalidParamsError("session, iterator and count are expected")
+		}
+
+		sidStr, _ := params[0].(string)
+		sid, err := uuid.Parse(sidStr)
+		if err != nil {
+			return nil, neorpc.NewInvalidParamsError("invalid session ID")
+		}
+
+		count, _ = params[2].(float64)
+
+		s, ok := n.sessions[sid]
+		if !ok {
+			return nil, neorpc.ErrUnknownSession
+		}
+
+		page := s.items[:min(int(count), len(s.items))]
+		s.items = s.items[len(page):]
+
+		res := make([]json.RawMessage, 0, len(page))
+		for _, it := range page {
+			data, err := stackitem.ToJSONWithTypes(it)
+			if err != nil {
+				return nil, neorpc.NewInternalServerError(err.Error())
+			}
+
+			res = append(res, data)
+		}
+
+		return res, nil
+	case "terminatesession":
+		var params []string
+
+		if err := json.Unmarshal(req.Params, &params); err != nil || len(params) != 1 {
+			return nil, neorpc.NewInvalidParamsError("session is expected")
+		}
+
+		sid, err := uuid.Parse(params[0])
+		if err != nil {
+			return nil, neorpc.NewInvalidParamsError("invalid session ID")
+		}
+
+		if _, ok := n.sessions[sid]; !ok {
+			return nil, neorpc.ErrUnknownSession
+		}
+
+		delete(n.sessions, sid)
+
+		return true, nil
+	default:
+		return nil, neorpc.NewMethodNotFoundError(req.Method)
+	}
+}
+
+func writeResponse(w http.ResponseWriter, id json.RawMessage, res any, rpcErr *neorpc.Error) {
+	out := response{JSONRPC: neorpc.JSONRPCVersion, ID: id, Error: rpcErr}
+
+	if rpcErr == nil {
+		data, err := json.Marshal(res)
+		if err != nil {
+			out.Error = neorpc.NewInternalServerError(err.Error())
+		} else {
+			out.Result = data
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,281 @@
+// Package rpctest records Neo JSON-RPC traffic into fixture files and serves
+// it back, so that RPC pool users can be tested without a running node.
+package rpctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+)
+
+// RecordEnv is the environment variable with the RPC node address, if it's
+// set [Server] records fixtures from this node instead of replaying them.
+const RecordEnv = "NEO_EXPORTER_RPC_RECORD"
+
+type (
+	// Interaction is a single recorded JSON-RPC call.
+	Interaction struct {
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+		Result json.RawMessage `json:"result,omitempty"`
+		Error  *neorpc.Error   `json:"error,omitempty"`
+	}
+
+	// Fixture is the list of recorded calls in the order they were made.
+	Fixture []Interaction
+
+	request struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+	}
+
+	response struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  json.RawMessage `json:"result,omitempty"`
+		Error   *neorpc.Error   `json:"error,omitempty"`
+	}
+)
+
+// Recorder is the HTTP handler forwarding JSON-RPC requests to the target
+// node and recording them.
+type Recorder struct {
+	target string
+	client *http.Client
+
+	mu       sync.Mutex
+	recorded Fixture
+}
+
+// NewRecorder creates Recorder forwarding requests to the given RPC node
+// address.
+func NewRecorder(target string) *Recorder {
+	return &Recorder{
+		target: target,
+		client: new(http.Client),
+	}
+}
+
+// ServeHTTP implements [http.Handler].
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	in, err := decodeRequest(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := r.client.Post(r.target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	var out response
+	if err := json.Unmarshal(data, &out); err != nil {
+		http.Error(w, fmt.Sprintf("decode response: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	r.mu.Lock()
+	r.recorded = append(r.recorded, Interaction{
+		Method: in.Method,
+		Params: in.Params,
+		Result: out.Result,
+		Error:  out.Error,
+	})
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(data)
+}
+
+// Fixture returns calls recorded so far.
+func (r *Recorder) Fixture() Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append(Fixture(nil), r.recorded...)
+}
+
+// Replayer is the HTTP handler answering JSON-RPC requests with recorded
+// responses. Calls with the same method and parameters are answered in the
+// recorded order, the last response is repeated when they are exhausted.
+type Replayer struct {
+	mu        sync.Mutex
+	responses map[string][]Interaction
+	missing   []string
+}
+
+// NewReplayer creates Replayer serving the fixture.
+func NewReplayer(f Fixture) *Replayer {
+	r := &Replayer{responses: make(map[string][]Interaction)}
+
+	for _, it := range f {
+		key := callKey(it.Method, it.Params)
+		r.responses[key] = append(r.responses[key], it)
+	}
+
+	return r
+}
+
+// ServeHTTP implements [http.Handler].
+func (r *Replayer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	in, err := decodeRequest(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	out := response{JSONRPC: neorpc.JSONRPCVersion, ID: in.ID}
+	key := callKey(in.Method, in.Params)
+
+	r.mu.Lock()
+	if queue := r.responses[key]; len(queue) != 0 {
+		out.Result, out.Error = queue[0].Result, queue[0].Error
+		if len(queue) > 1 {
+			r.responses[key] = queue[1:]
+		}
+	} else {
+		r.missing = append(r.missing, key)
+		out.Error = neorpc.NewInternalServerError("no recorded response for " + key)
+	}
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// Missing returns requests that have no recorded responses.
+func (r *Replayer) Missing() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.missing...)
+}
+
+// Load reads the fixture from the file.
+func Load(path string) (Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", path, err)
+	}
+
+	return f, nil
+}
+
+// Save writes the fixture to the file creating missing directories.
+func Save(path string, f Fixture) error {
+	data, err := json.MarshalIndent(f, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Server starts HTTP server replaying the fixture and returns its address.
+// If [RecordEnv] is set, the server forwards requests to the node instead and
+// overwrites the fixture with them when the test finishes. Replayed test
+// fails if requests missing in the fixture are made.
+func Server(t testing.TB, path string) string {
+	if target := os.Getenv(RecordEnv); target != "" {
+		rec := NewRecorder(target)
+		srv := httptest.NewServer(rec)
+
+		t.Cleanup(func() {
+			srv.Close()
+
+			if err := Save(path, rec.Fixture()); err != nil {
+				t.Errorf("save fixture: %v", err)
+			}
+		})
+
+		return srv.URL
+	}
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("load fixture: %v", err)
+	}
+
+	rep := NewReplayer(f)
+	srv := httptest.NewServer(rep)
+
+	t.Cleanup(func() {
+		srv.Close()
+
+		if missing := rep.Missing(); len(missing) != 0 {
+			t.Errorf("requests missing in %s, record it again with %s set: %v", path, RecordEnv, missing)
+		}
+	})
+
+	return srv.URL
+}
+
+func decodeRequest(body []byte) (request, error) {
+	var req request
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return req, fmt.Errorf("decode request: %w", err)
+	}
+
+	if req.Method == "" {
+		return req, errors.New("batch and notification requests are not supported")
+	}
+
+	return req, nil
+}
+
+// callKey identifies the call by its method and parameters regardless of
+// parameters formatting.
+func callKey(method string, params json.RawMessage) string {
+	var buf bytes.Buffer
+
+	if err := json.Compact(&buf, params); err != nil {
+		buf.Reset()
+		buf.Write(params)
+	}
+
+	return method + buf.String()
+}
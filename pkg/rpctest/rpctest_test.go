@@ -0,0 +1,69 @@
+package rpctest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	var count int
+
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		count++
+		_ = json.NewEncoder(w).Encode(response{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(strings.Repeat("1", count))})
+	}))
+	t.Cleanup(node.Close)
+
+	rec := NewRecorder(node.URL)
+	recSrv := httptest.NewServer(rec)
+	t.Cleanup(recSrv.Close)
+
+	require.Equal(t, `1`, string(post(t, recSrv.URL, 1, `[]`).Result))
+	require.Equal(t, `11`, string(post(t, recSrv.URL, 2, `[]`).Result))
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, Save(path, rec.Fixture()))
+
+	f, err := Load(path)
+	require.NoError(t, err)
+
+	rep := NewReplayer(f)
+	repSrv := httptest.NewServer(rep)
+	t.Cleanup(repSrv.Close)
+
+	// Responses are served in the recorded order regardless of request IDs
+	// and parameters formatting, the last one is repeated.
+	resp := post(t, repSrv.URL, 7, `[ ]`)
+	require.Equal(t, `7`, string(resp.ID))
+	require.Equal(t, `1`, string(resp.Result))
+	require.Equal(t, `11`, string(post(t, repSrv.URL, 8, `[]`).Result))
+	require.Equal(t, `11`, string(post(t, repSrv.URL, 9, `[]`).Result))
+	require.Empty(t, rep.Missing())
+
+	resp = post(t, repSrv.URL, 10, `["unknown"]`)
+	require.NotNil(t, resp.Error)
+	require.Len(t, rep.Missing(), 1)
+}
+
+func post(t *testing.T, url string, id int, params string) response {
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"getblockcount","params":%s}`, id, params)
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var res response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+
+	return res
+}